@@ -28,8 +28,7 @@ that contain the specified name in their import path.`,
 		// Parse the binary
 		info, err := gobinaryparser.ParseBinaryFromFile(binaryPath)
 		if err != nil {
-			errorColor.Fprintf(os.Stderr, "Error parsing binary: %v\n", err)
-			os.Exit(1)
+			exitWithError("Error parsing binary", err)
 		}
 
 		// Find matching dependencies
@@ -39,11 +38,18 @@ that contain the specified name in their import path.`,
 			// Exact match
 			if dep := info.GetDependencyByPath(dependencyName); dep != nil {
 				matchingDeps = append(matchingDeps, *dep)
+			} else if effectiveFlag {
+				for _, dep := range info.Dependencies {
+					if dep.Effective().Path == dependencyName {
+						matchingDeps = append(matchingDeps, dep)
+					}
+				}
 			}
 		} else {
-			// Partial match - 查找路径中包含指定名称的所有依赖
+			// Partial match - 查找路径中包含指定名称的所有依赖，--effective 时也匹配替换后的路径
 			for _, dep := range info.Dependencies {
-				if strings.Contains(dep.Path, dependencyName) {
+				if strings.Contains(dep.Path, dependencyName) ||
+					(effectiveFlag && strings.Contains(dep.Effective().Path, dependencyName)) {
 					matchingDeps = append(matchingDeps, dep)
 				}
 			}
@@ -62,43 +68,37 @@ that contain the specified name in their import path.`,
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
 		if verboseFlag {
-			tableHeaderColor.Fprintln(w, "MODULE\tVERSION\tSUM\tREPLACED BY")
+			tableHeaderColor.Fprintf(w, "MODULE\tVERSION\tSUM\t%s\n", replaceColumnHeader())
 			for _, dep := range matchingDeps {
-				replacedBy := ""
-				if dep.Replace != nil {
-					replacedBy = fmt.Sprintf("%s@%s", dep.Replace.Path, dep.Replace.Version)
-				}
+				row := newDependencyRow(dep)
 
 				// Highlight the matching part in the module path
-				path := dep.Path
+				path := row.path
 				if !findExactFlag {
 					path = highlightSubstring(path, dependencyName)
 				} else {
 					moduleColor.Fprintf(w, "%s\t", path)
 				}
 
-				versionColor.Fprintf(w, "%s\t", dep.Version)
-				fmt.Fprintf(w, "%s\t", dep.Sum)
+				versionColor.Fprintf(w, "%s\t", row.version)
+				fmt.Fprintf(w, "%s\t", row.sum)
 
-				if replacedBy != "" {
-					replacedColor.Fprintf(w, "%s", replacedBy)
+				if row.replace != "" {
+					replacedColor.Fprintf(w, "%s", row.replace)
 				}
 				fmt.Fprintln(w)
 			}
 		} else {
-			tableHeaderColor.Fprintln(w, "MODULE\tVERSION\tREPLACED BY")
+			tableHeaderColor.Fprintf(w, "MODULE\tVERSION\t%s\n", replaceColumnHeader())
 			for _, dep := range matchingDeps {
-				replacedBy := ""
-				if dep.Replace != nil {
-					replacedBy = fmt.Sprintf("%s@%s", dep.Replace.Path, dep.Replace.Version)
-				}
+				row := newDependencyRow(dep)
 
 				// Print with colors
-				moduleColor.Fprintf(w, "%s\t", dep.Path)
-				versionColor.Fprintf(w, "%s\t", dep.Version)
+				moduleColor.Fprintf(w, "%s\t", row.path)
+				versionColor.Fprintf(w, "%s\t", row.version)
 
-				if replacedBy != "" {
-					replacedColor.Fprintf(w, "%s", replacedBy)
+				if row.replace != "" {
+					replacedColor.Fprintf(w, "%s", row.replace)
 				}
 				fmt.Fprintln(w)
 			}
@@ -113,4 +113,5 @@ func initFindCmd() {
 	// Find command flags
 	findCmd.Flags().BoolVarP(&findExactFlag, "exact", "e", false, "Match the dependency name exactly")
 	findCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed information including checksums")
+	findCmd.Flags().BoolVar(&effectiveFlag, "effective", false, "Show the module that was actually linked instead of the requested one")
 }
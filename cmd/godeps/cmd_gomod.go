@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+	"github.com/spf13/cobra"
+)
+
+// Gomod command flags
+var (
+	gomodOutputFlag string
+	gomodForceFlag  bool
+)
+
+// gomodCmd represents the gomod command to reconstruct go.mod and go.sum from a binary
+var gomodCmd = &cobra.Command{
+	Use:   "gomod [flags] <go-binary-file>",
+	Short: "Reconstruct go.mod and go.sum from a Go binary file",
+	Long: `Reconstruct the go.mod and go.sum files of the main module from the build info of a Go binary.
+
+The go.mod contains the module path, a go directive derived from the Go version, a require
+line for every dependency and the replace directives. The go.sum contains the checksums
+recorded in the binary; the /go.mod checksums are not recorded and are added by the go
+command on the first build.
+
+Without --output the go.mod is printed to standard output.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		binaryPath := args[0]
+
+		info, err := gobinaryparser.ParseBinaryFromFile(binaryPath)
+		if err != nil {
+			exitWithError("Error parsing binary", err)
+		}
+
+		gomod, err := info.GoMod()
+		if err != nil {
+			exitWithError("Error reconstructing go.mod", err)
+		}
+
+		if gomodOutputFlag == "" {
+			fmt.Print(string(gomod))
+			return
+		}
+
+		gomodPath := filepath.Join(gomodOutputFlag, "go.mod")
+		gosumPath := filepath.Join(gomodOutputFlag, "go.sum")
+		if !gomodForceFlag {
+			for _, path := range []string{gomodPath, gosumPath} {
+				if _, err := os.Stat(path); err == nil {
+					errorColor.Fprintf(os.Stderr, "%s already exists, use --force to overwrite it\n", path)
+					os.Exit(exitError)
+				}
+			}
+		}
+
+		if err := os.MkdirAll(gomodOutputFlag, 0o755); err != nil {
+			exitWithError("Error creating output directory", err)
+		}
+		if err := os.WriteFile(gomodPath, gomod, 0o644); err != nil {
+			exitWithError("Error writing go.mod", err)
+		}
+		if err := os.WriteFile(gosumPath, info.GoSum(), 0o644); err != nil {
+			exitWithError("Error writing go.sum", err)
+		}
+
+		successColor.Printf("✅ Wrote %s and %s\n", gomodPath, gosumPath)
+		if info.Degraded != nil {
+			warnColor.Println("⚠️  No build info found, dependencies were recovered heuristically and may be incomplete")
+		}
+	},
+}
+
+// initGomodCmd initializes the gomod command
+func initGomodCmd() {
+	gomodCmd.Flags().StringVarP(&gomodOutputFlag, "output", "o", "", "Directory to write go.mod and go.sum to")
+	gomodCmd.Flags().BoolVarP(&gomodForceFlag, "force", "f", false, "Overwrite existing go.mod and go.sum files")
+}
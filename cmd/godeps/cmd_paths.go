@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+	"github.com/spf13/cobra"
+)
+
+// Paths command flags
+var (
+	pathsLeaksFlag bool
+	pathsKindFlag  string
+)
+
+// pathsCmd represents the paths command to audit the source file paths recorded in a binary
+var pathsCmd = &cobra.Command{
+	Use:   "paths [flags] <go-binary-file>",
+	Short: "List the source file paths recorded in a Go binary",
+	Long: `List the source file paths recorded in the pclntab file table of a Go binary and
+classify them as GOROOT, module cache, vendor, workspace or generated files.
+
+Binaries built without -trimpath record absolute paths, which leak the home directory
+of the developer and the workspace layout of CI systems. With --leaks only the usernames,
+hostnames and CI workspaces found in the paths are reported, and the command exits with
+code 1 when there are any.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		binaryPath := args[0]
+
+		report, err := gobinaryparser.AnalyzeSourcePaths(binaryPath)
+		if err != nil {
+			exitWithError("Error reading source paths", err)
+		}
+
+		if pathsKindFlag != "" {
+			var files []gobinaryparser.SourcePath
+			for _, f := range report.Files {
+				if string(f.Kind) == pathsKindFlag {
+					files = append(files, f)
+				}
+			}
+			report.Files = files
+		}
+
+		if jsonOutputFlag {
+			var output interface{} = report
+			if pathsLeaksFlag {
+				leaks := report.Leaks
+				if leaks == nil {
+					leaks = []gobinaryparser.PathLeak{}
+				}
+				output = leaks
+			}
+			jsonData, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				errorColor.Fprintf(os.Stderr, "Error encoding to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonData))
+		} else if pathsLeaksFlag {
+			printPathLeaks(binaryPath, report)
+		} else {
+			printSourcePaths(binaryPath, report)
+		}
+
+		if pathsLeaksFlag && len(report.Leaks) > 0 {
+			os.Exit(exitError)
+		}
+	},
+}
+
+// printSourcePaths prints the classified source file paths and their roots
+func printSourcePaths(binaryPath string, report *gobinaryparser.SourcePathReport) {
+	headerColor.Println("📂 Go Binary Source Paths")
+	fmt.Println()
+
+	subHeaderColor.Print("Binary: ")
+	fmt.Println(binaryPath)
+	subHeaderColor.Print("Trimmed: ")
+	if report.Trimmed {
+		successColor.Println("yes (built with -trimpath)")
+	} else {
+		warnColor.Println("no (absolute source paths are recorded)")
+	}
+	fmt.Println()
+
+	if len(report.Roots) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		tableHeaderColor.Fprintln(w, "ROOT\tKIND\tFILES")
+		for _, root := range report.Roots {
+			moduleColor.Fprintf(w, "%s\t", root.Path)
+			fmt.Fprintf(w, "%s\t%d\n", root.Kind, root.Files)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	tableHeaderColor.Fprintln(w, "KIND\tPATH")
+	for _, f := range report.Files {
+		switch f.Kind {
+		case gobinaryparser.SourcePathGoroot, gobinaryparser.SourcePathGenerated:
+			stdlibColor.Fprintf(w, "%s\t", f.Kind)
+		default:
+			moduleColor.Fprintf(w, "%s\t", f.Kind)
+		}
+		fmt.Fprintf(w, "%s\n", f.Path)
+	}
+	w.Flush()
+
+	if len(report.Leaks) > 0 {
+		fmt.Println()
+		warnColor.Printf("⚠️  The paths leak %d username(s), hostname(s) or CI workspace(s), run with --leaks for details\n", len(report.Leaks))
+	}
+}
+
+// printPathLeaks prints the usernames, hostnames and CI workspaces leaked by the source paths
+func printPathLeaks(binaryPath string, report *gobinaryparser.SourcePathReport) {
+	if len(report.Leaks) == 0 {
+		if report.Trimmed {
+			successColor.Printf("✅ %s was built with -trimpath, no absolute source paths are recorded\n", binaryPath)
+		} else {
+			successColor.Printf("✅ No usernames, hostnames or CI workspaces found in the source paths of %s\n", binaryPath)
+		}
+		return
+	}
+
+	warnColor.Printf("⚠️  %s leaks build environment details through its source paths:\n\n", binaryPath)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	tableHeaderColor.Fprintln(w, "KIND\tVALUE\tFILES\tEXAMPLE")
+	for _, leak := range report.Leaks {
+		fmt.Fprintf(w, "%s\t", leak.Kind)
+		errorColor.Fprintf(w, "%s\t", leak.Value)
+		fmt.Fprintf(w, "%d\t%s\n", leak.Files, leak.Example)
+	}
+	w.Flush()
+	fmt.Println()
+	fmt.Println("Rebuild with -trimpath to record module-relative paths instead.")
+}
+
+// initPathsCmd initializes the paths command
+func initPathsCmd() {
+	pathsCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output in JSON format")
+	pathsCmd.Flags().BoolVar(&pathsLeaksFlag, "leaks", false, "Only report leaked usernames, hostnames and CI workspaces, exit with code 1 if any are found")
+	pathsCmd.Flags().StringVar(&pathsKindFlag, "kind", "", "Only list paths of the given kind (goroot, module-cache, vendor, workspace, generated)")
+}
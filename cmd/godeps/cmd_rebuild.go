@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+	"github.com/spf13/cobra"
+)
+
+// Rebuild command flags
+var rebuildStrictFlag bool
+
+// rebuildCmd represents the rebuild-cmd command to reconstruct the build invocation of a binary
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild-cmd [flags] <go-binary-file>",
+	Short: "Print a shell script that rebuilds a Go binary file",
+	Long: `Reconstruct the go build invocation of a Go binary from its build settings and print
+it as a shell script: check out the recorded VCS revision, export the build environment
+(GOTOOLCHAIN, CGO_ENABLED, GOOS, GOARCH, GOAMD64, ...) and run go build with the recorded
+-tags, -ldflags, -gcflags, -trimpath and -buildmode flags.
+
+Settings that make an exact reproduction impossible, such as uncommitted changes, local
+replace directives or a missing revision, are listed as warnings at the top of the script
+and on standard error. With --strict the command exits with code 1 when there are any.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		binaryPath := args[0]
+
+		info, err := gobinaryparser.ParseBinaryFromFile(binaryPath)
+		if err != nil {
+			exitWithError("Error parsing binary", err)
+		}
+
+		plan := info.RebuildPlan()
+
+		if jsonOutputFlag {
+			jsonData, err := json.MarshalIndent(plan, "", "  ")
+			if err != nil {
+				errorColor.Fprintf(os.Stderr, "Error encoding to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonData))
+		} else {
+			fmt.Print(plan.Script())
+		}
+
+		for _, issue := range plan.Issues {
+			warnColor.Fprintf(os.Stderr, "⚠️  %s\n", issue.Message)
+		}
+		if rebuildStrictFlag && !plan.Reproducible() {
+			os.Exit(exitError)
+		}
+	},
+}
+
+// initRebuildCmd initializes the rebuild-cmd command
+func initRebuildCmd() {
+	rebuildCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output the rebuild plan in JSON format")
+	rebuildCmd.Flags().BoolVar(&rebuildStrictFlag, "strict", false, "Exit with code 1 if the build cannot be reproduced exactly")
+}
@@ -14,11 +14,16 @@ var (
 	jsonOutputFlag   bool
 	verboseFlag      bool
 	showReplacedFlag bool
+	effectiveFlag    bool
+	deepScanFlag     bool
+	goVersionFlag    string
+	maxDownloadFlag  string
+	noProgressFlag   bool
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "godeps [flags] <go-binary-file>",
+	Use:   "godeps [flags] <go-binary-file | url>",
 	Short: "Parse and display dependencies from Go binary files",
 	Long: `godeps is a tool that parses Go binary files and displays their dependencies.
 
@@ -27,7 +32,24 @@ It extracts information such as:
   • Go version used for building
   • All module dependencies and their versions
   • Replaced dependencies
-  • Build settings`,
+  • Build settings
+
+Besides executables, it accepts shared libraries built with -buildmode=c-shared
+or -buildmode=plugin and static libraries (.a) built with -buildmode=c-archive.
+
+An http:// or https:// URL is downloaded before parsing. A progress bar is shown
+on stderr when it is a terminal, and downloads larger than --max-download are
+aborted as soon as the size is known.
+
+Exit codes:
+  0  success
+  1  generic error or invalid usage
+  3  file not found (or HTTP 404/410)
+  4  not a Go binary
+  5  Go binary without readable build info
+  6  unsupported file format
+  7  input exceeds the size limit
+  8  Go version does not satisfy --go-version`,
 	// 阻止Cobra将参数尝试解析为子命令
 	DisableFlagParsing: false,
 	Args: func(cmd *cobra.Command, args []string) error {
@@ -46,29 +68,59 @@ It extracts information such as:
 
 		binaryPath := args[0]
 
-		// Parse the binary
-		info, err := gobinaryparser.ParseBinaryFromFile(binaryPath)
-		if err != nil {
-			errorColor.Fprintf(os.Stderr, "Error parsing binary: %v\n", err)
-			os.Exit(1)
+		var constraint *gobinaryparser.GoVersionConstraint
+		if goVersionFlag != "" {
+			var err error
+			if constraint, err = gobinaryparser.ParseGoVersionConstraint(goVersionFlag); err != nil {
+				exitWithError("Invalid --go-version", err)
+			}
 		}
 
-		// Filter dependencies if needed
-		dependencies := info.Dependencies
-		if filterStdLibFlag {
-			dependencies = gobinaryparser.FilterStdLib(info.Dependencies)
+		// Download URLs with a progress bar instead of reading a local file
+		if isURL(binaryPath) {
+			info, err := parseURL(binaryPath)
+			if err != nil {
+				exitWithError("Error parsing binary", err)
+			}
+			checkGoVersion(constraint, binaryPath, info.GoVersion)
+			if jsonOutputFlag {
+				printJSON(info, selectDependencies(info))
+				return
+			}
+			printInfo(info, selectDependencies(info))
+			return
 		}
 
-		if showReplacedFlag {
-			// 筛选出有替换的依赖
-			replaced := make([]gobinaryparser.DependencyInfo, 0)
-			for _, dep := range dependencies {
-				if dep.Replace != nil {
-					replaced = append(replaced, dep)
+		// Universal (fat) Mach-O binaries contain one Go binary per architecture
+		if isUniversalFile(binaryPath) {
+			universal, err := gobinaryparser.ParseUniversalBinary(binaryPath)
+			if err != nil {
+				exitWithError("Error parsing universal binary", err)
+			}
+			var versions []string
+			for _, slice := range universal.Slices {
+				if slice.Info != nil {
+					versions = append(versions, slice.Info.GoVersion)
 				}
 			}
-			dependencies = replaced
+			checkGoVersion(constraint, binaryPath, versions...)
+			if jsonOutputFlag {
+				printUniversalJSON(universal)
+				return
+			}
+			printUniversalInfo(universal)
+			return
+		}
+
+		// Parse the binary, searching the whole file for embedded Go binaries with --deep
+		parser := gobinaryparser.NewParser(gobinaryparser.WithDeepScan(deepScanFlag))
+		info, err := parser.ParseBinaryFromFile(binaryPath)
+		if err != nil {
+			exitWithError("Error parsing binary", err)
 		}
+		checkGoVersion(constraint, binaryPath, info.GoVersion)
+
+		dependencies := selectDependencies(info)
 
 		// Output in JSON format if requested
 		if jsonOutputFlag {
@@ -81,6 +133,75 @@ It extracts information such as:
 	},
 }
 
+// parseURL downloads and parses the binary at url, showing a progress bar on stderr
+// when it is a terminal and aborting downloads larger than --max-download
+func parseURL(url string) (*gobinaryparser.BinaryInfo, error) {
+	opts := []gobinaryparser.Option{gobinaryparser.WithDeepScan(deepScanFlag), gobinaryparser.WithTimeout(0)}
+	if maxDownloadFlag != "" {
+		size, err := parseByteSize(maxDownloadFlag)
+		if err != nil {
+			exitWithError("Invalid --max-download", err)
+		}
+		opts = append(opts, gobinaryparser.WithMaxDownloadSize(size))
+	}
+	if !noProgressFlag && isTerminal(os.Stderr) {
+		opts = append(opts, gobinaryparser.WithProgress(newProgressBar()))
+	}
+	return gobinaryparser.NewParser(opts...).ParseBinaryFromURL(url)
+}
+
+// selectDependencies applies the root command's filter flags to the dependencies of a binary
+func selectDependencies(info *gobinaryparser.BinaryInfo) []gobinaryparser.DependencyInfo {
+	// Filter dependencies if needed
+	dependencies := info.Dependencies
+	if filterStdLibFlag {
+		dependencies = gobinaryparser.FilterStdLib(info.Dependencies)
+	}
+
+	if showReplacedFlag {
+		// 筛选出有替换的依赖
+		replaced := make([]gobinaryparser.DependencyInfo, 0)
+		for _, dep := range dependencies {
+			if dep.Replace != nil {
+				replaced = append(replaced, dep)
+			}
+		}
+		dependencies = replaced
+	}
+
+	return dependencies
+}
+
+// checkGoVersion exits with exitGoVersionMismatch unless one of the Go versions
+// satisfies the constraint. A nil constraint accepts every binary.
+func checkGoVersion(constraint *gobinaryparser.GoVersionConstraint, path string, versions ...string) {
+	if constraint == nil {
+		return
+	}
+	for _, version := range versions {
+		if v, err := gobinaryparser.ParseGoVersion(version); err == nil && constraint.Check(v) {
+			return
+		}
+	}
+
+	version := "unknown"
+	if len(versions) > 0 && versions[0] != "" {
+		version = versions[0]
+	}
+	warnColor.Fprintf(os.Stderr, "%s: Go version %s does not satisfy %s\n", path, version, constraint)
+	os.Exit(exitGoVersionMismatch)
+}
+
+// isUniversalFile reports whether the file at path is a Mach-O universal binary
+func isUniversalFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	return gobinaryparser.IsUniversalBinary(f)
+}
+
 // initCommands initializes all commands and their flags
 func initCommands() {
 	// Root command flags
@@ -88,12 +209,27 @@ func initCommands() {
 	rootCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output in JSON format")
 	rootCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed information including checksums")
 	rootCmd.Flags().BoolVarP(&showReplacedFlag, "replaced", "r", false, "Only show dependencies that have been replaced")
+	rootCmd.Flags().BoolVar(&effectiveFlag, "effective", false, "Show the module that was actually linked instead of the requested one")
+	rootCmd.Flags().BoolVar(&deepScanFlag, "deep", false, "Search the whole file for embedded Go binaries")
+	rootCmd.Flags().StringVar(&maxDownloadFlag, "max-download", "", "Maximum size of a binary downloaded from a URL (e.g. 512MB, 2GiB), default 2GiB")
+	rootCmd.Flags().BoolVar(&noProgressFlag, "no-progress", false, "Do not show a progress bar when downloading from a URL")
+	rootCmd.Flags().StringVar(&goVersionFlag, "go-version", "", "Only accept binaries whose Go version satisfies the constraint (e.g. \"<go1.21.9\" or \">=1.21, <1.22.5\")")
 
 	// Initialize subcommands
 	initFindCmd()
 	initStdlibCmd()
+	initSizeCmd()
+	initGomodCmd()
+	initRebuildCmd()
+	initVarsCmd()
+	initPathsCmd()
 
 	// Add subcommands to root command
 	rootCmd.AddCommand(findCmd)
 	rootCmd.AddCommand(stdlibCmd)
+	rootCmd.AddCommand(sizeCmd)
+	rootCmd.AddCommand(gomodCmd)
+	rootCmd.AddCommand(rebuildCmd)
+	rootCmd.AddCommand(varsCmd)
+	rootCmd.AddCommand(pathsCmd)
 }
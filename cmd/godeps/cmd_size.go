@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+	"github.com/spf13/cobra"
+)
+
+// Size command flags
+var sizeTopFlag int
+
+// sizeCmd represents the size command to attribute binary size to modules
+var sizeCmd = &cobra.Command{
+	Use:   "size [flags] <go-binary-file>",
+	Short: "Show how much of the binary size each module accounts for",
+	Long: `Attribute the code and data bytes of a Go binary to the modules it depends on.
+
+Code bytes are attributed using the function ranges in the binary's pclntab and data
+bytes using the symbol table. Standard library, Go runtime, cgo and compiler-generated
+metadata are reported as separate buckets.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		binaryPath := args[0]
+
+		report, err := gobinaryparser.AnalyzeSize(binaryPath)
+		if err != nil {
+			exitWithError("Error analyzing binary size", err)
+		}
+
+		modules := report.Modules
+		if sizeTopFlag > 0 && len(modules) > sizeTopFlag {
+			modules = modules[:sizeTopFlag]
+		}
+
+		if jsonOutputFlag {
+			output := *report
+			output.Modules = modules
+			jsonData, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				errorColor.Fprintf(os.Stderr, "Error encoding to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonData))
+			return
+		}
+
+		headerColor.Println("📏 Go Binary Size Attribution")
+		fmt.Println()
+
+		subHeaderColor.Print("Binary: ")
+		fmt.Println(binaryPath)
+		subHeaderColor.Print("File size: ")
+		highlightColor.Println(formatBytes(uint64(report.FileSize)))
+		subHeaderColor.Print("Sections: ")
+		fmt.Printf("text %s, rodata %s, data %s\n",
+			formatBytes(report.TextSize), formatBytes(report.RodataSize), formatBytes(report.DataSize))
+		subHeaderColor.Print("Attributed: ")
+		highlightColor.Println(formatBytes(report.AttributedSize))
+		if !report.HasSymbols {
+			warnColor.Println("⚠️  No symbol table found (stripped binary), only code bytes are attributed")
+		}
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		tableHeaderColor.Fprintln(w, "MODULE\tKIND\tTEXT\tRODATA\tDATA\tTOTAL\tPERCENT")
+		for _, m := range modules {
+			name := m.Path
+			if m.Version != "" {
+				name += "@" + m.Version
+			}
+
+			switch m.Kind {
+			case gobinaryparser.SizeBucketModule, gobinaryparser.SizeBucketMain:
+				moduleColor.Fprintf(w, "%s\t", name)
+			default:
+				stdlibColor.Fprintf(w, "%s\t", name)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t", m.Kind,
+				formatBytes(m.TextBytes), formatBytes(m.RodataBytes), formatBytes(m.DataBytes))
+			highlightColor.Fprintf(w, "%s\t", formatBytes(m.Total()))
+			fmt.Fprintf(w, "%.2f%%\n", m.Percent)
+		}
+		w.Flush()
+
+		if len(modules) < len(report.Modules) {
+			fmt.Printf("\n... %d more modules not shown\n", len(report.Modules)-len(modules))
+		}
+	},
+}
+
+// initSizeCmd initializes the size command
+func initSizeCmd() {
+	sizeCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output in JSON format")
+	sizeCmd.Flags().IntVarP(&sizeTopFlag, "top", "n", 0, "Only show the N largest modules")
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 
 	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
@@ -13,62 +13,73 @@ import (
 var stdlibCmd = &cobra.Command{
 	Use:   "stdlib [flags] <go-binary-file>",
 	Short: "Show only standard library dependencies",
-	Long:  "Parse a Go binary file and show only the standard library dependencies used by the binary.",
+	Long: `Parse a Go binary file and show the standard library packages linked into the binary.
+
+Packages are enumerated from the function symbols in the binary's pclntab, so the
+result reflects the packages that are actually linked (for example net/http or
+crypto/tls), not just the modules recorded in the build info.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		binaryPath := args[0]
 
-		// Parse the binary
-		info, err := gobinaryparser.ParseBinaryFromFile(binaryPath)
+		// List all linked packages
+		packages, err := gobinaryparser.ListPackages(binaryPath)
 		if err != nil {
-			errorColor.Fprintf(os.Stderr, "Error parsing binary: %v\n", err)
-			os.Exit(1)
+			exitWithError("Error listing packages", err)
 		}
 
-		// 筛选标准库依赖（使用IsStdLib函数）
-		var stdlibDeps []gobinaryparser.DependencyInfo
-		for _, dep := range info.Dependencies {
-			if gobinaryparser.IsStdLib(dep.Path) {
-				stdlibDeps = append(stdlibDeps, dep)
+		var stdlibPkgs []gobinaryparser.PackageInfo
+		for _, pkg := range packages {
+			if pkg.StdLib {
+				stdlibPkgs = append(stdlibPkgs, pkg)
 			}
 		}
 
 		// Print results
-		headerColor.Printf("📚 Standard Library Dependencies (%d)\n\n", len(stdlibDeps))
+		headerColor.Printf("📚 Standard Library Dependencies (%d)\n\n", len(stdlibPkgs))
 
 		// Group by package prefix (like net/, context, fmt, etc)
-		groups := make(map[string][]string)
-
-		for _, dep := range stdlibDeps {
-			parts := strings.SplitN(dep.Path, "/", 2)
-			prefix := parts[0]
+		groups := make(map[string][]gobinaryparser.PackageInfo)
+		var prefixes []string
 
-			if len(parts) > 1 {
-				groups[prefix] = append(groups[prefix], parts[1])
-			} else {
-				groups[prefix] = append(groups[prefix], "")
+		for _, pkg := range stdlibPkgs {
+			prefix := strings.SplitN(pkg.Path, "/", 2)[0]
+			if _, ok := groups[prefix]; !ok {
+				prefixes = append(prefixes, prefix)
 			}
+			groups[prefix] = append(groups[prefix], pkg)
 		}
+		sort.Strings(prefixes)
 
 		// Print grouped packages
-		for prefix, subPackages := range groups {
-			stdlibColor.Printf("%s\n", prefix)
-
-			if len(subPackages[0]) > 0 { // Not a single package
-				for _, subPkg := range subPackages {
-					fmt.Printf("  ├─ %s\n", subPkg)
+		for _, prefix := range prefixes {
+			stdlibColor.Printf("%s", prefix)
+			for _, pkg := range groups[prefix] {
+				if pkg.Path == prefix && verboseFlag {
+					fmt.Printf(" (%d funcs)", pkg.Functions)
 				}
 			}
 			fmt.Println()
+
+			for _, pkg := range groups[prefix] {
+				if pkg.Path == prefix {
+					continue
+				}
+				fmt.Printf("  ├─ %s", strings.TrimPrefix(pkg.Path, prefix+"/"))
+				if verboseFlag {
+					fmt.Printf(" (%d funcs)", pkg.Functions)
+				}
+				fmt.Println()
+			}
 		}
 
 		// Print summary
 		fmt.Println()
 		subHeaderColor.Print("Total: ")
-		highlightColor.Printf("%d standard library packages\n", len(stdlibDeps))
+		highlightColor.Printf("%d standard library packages\n", len(stdlibPkgs))
 	},
 }
 
 // initStdlibCmd initializes the stdlib command
 func initStdlibCmd() {
-	// No specific flags for stdlib command currently
+	stdlibCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show the number of linked functions per package")
 }
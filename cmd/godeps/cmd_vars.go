@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+	"github.com/spf13/cobra"
+)
+
+// varsCmd represents the vars command to print the variables set with -ldflags -X
+var varsCmd = &cobra.Command{
+	Use:   "vars [flags] <go-binary-file> [variable...]",
+	Short: "Print the string variables set with -ldflags -X",
+	Long: `Print the values of string variables that were set at link time with
+-ldflags "-X importpath.name=value", such as main.version or main.commit.
+
+Without variable names, the variables of all -X flags recorded in the -ldflags build
+setting are printed. The go command does not record -ldflags for -trimpath builds; pass
+the variable names explicitly in that case. The values are read through the symbol
+table, so binaries linked with -s are not supported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		binaryPath := args[0]
+
+		vars, err := gobinaryparser.ReadLinkerVars(binaryPath, args[1:]...)
+		if err != nil {
+			exitWithError("Error reading linker variables", err)
+		}
+
+		if jsonOutputFlag {
+			if vars == nil {
+				vars = []gobinaryparser.LinkerVar{}
+			}
+			jsonData, err := json.MarshalIndent(vars, "", "  ")
+			if err != nil {
+				errorColor.Fprintf(os.Stderr, "Error encoding to JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonData))
+		} else if len(vars) == 0 {
+			warnColor.Println("⚠️  No -X flags recorded in the build settings, pass the variable names to read")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			tableHeaderColor.Fprintln(w, "VARIABLE\tVALUE")
+			for _, v := range vars {
+				moduleColor.Fprintf(w, "%s\t", v.Name)
+				if v.Found {
+					versionColor.Fprintf(w, "%q\n", v.Value)
+				} else {
+					errorColor.Fprintf(w, "%s\n", v.Error)
+				}
+			}
+			w.Flush()
+		}
+
+		for _, v := range vars {
+			if !v.Found {
+				os.Exit(exitError)
+			}
+		}
+	},
+}
+
+// initVarsCmd initializes the vars command
+func initVarsCmd() {
+	varsCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output in JSON format")
+}
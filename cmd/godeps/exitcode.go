@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+)
+
+// Exit codes returned by godeps. Parse failures use a distinct code per cause
+// so that scripts can tell a missing file from a non-Go binary.
+const (
+	exitError             = 1 // generic error, including usage errors
+	exitNotFound          = 3 // file does not exist or the server returned 404/410
+	exitNotGoBinary       = 4 // input is not a Go binary
+	exitNoBuildInfo       = 5 // Go binary whose build info cannot be read or recovered
+	exitUnsupportedFormat = 6 // input is not a supported executable format
+	exitTooLarge          = 7 // input exceeds the size limit
+	exitGoVersionMismatch = 8 // Go version does not satisfy --go-version
+)
+
+// exitCodes maps the library's sentinel errors to exit codes, checked in order
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{gobinaryparser.ErrNotFound, exitNotFound},
+	{gobinaryparser.ErrTooLarge, exitTooLarge},
+	{gobinaryparser.ErrUnsupportedFormat, exitUnsupportedFormat},
+	{gobinaryparser.ErrNotGoBinary, exitNotGoBinary},
+	{gobinaryparser.ErrNoBuildInfo, exitNoBuildInfo},
+}
+
+// exitCode returns the exit code for an error returned by the parser
+func exitCode(err error) int {
+	for _, c := range exitCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return exitError
+}
+
+// exitWithError prints err after the given message and exits with the matching exit code
+func exitWithError(message string, err error) {
+	errorColor.Fprintf(os.Stderr, "%s: %v\n", message, err)
+	os.Exit(exitCode(err))
+}
@@ -39,10 +39,15 @@ func main() {
 // isCommand 检查参数是否是已知的子命令
 func isCommand(arg string) bool {
 	knownCommands := map[string]bool{
-		"find":       true,
-		"stdlib":     true,
-		"completion": true,
-		"help":       true,
+		"find":        true,
+		"stdlib":      true,
+		"size":        true,
+		"gomod":       true,
+		"rebuild-cmd": true,
+		"vars":        true,
+		"paths":       true,
+		"completion":  true,
+		"help":        true,
 	}
 	return knownCommands[arg]
 }
@@ -93,6 +98,91 @@ func setupCustomErrorHandling() {
 		}
 		return nil
 	}
+
+	// Configure size command
+	sizeCmd.SilenceErrors = true
+	sizeCmd.SilenceUsage = true
+
+	sizeCmd.Args = nil
+	sizeCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			errorColor.Fprintf(os.Stderr, "❌ Error: size命令需要一个二进制文件路径参数\n\n")
+			warnColor.Fprintf(os.Stderr, "正确用法：\n")
+			fmt.Fprintf(os.Stderr, "  godeps size <go-binary-file>\n\n")
+			fmt.Fprintf(os.Stderr, "例如：\n")
+			fmt.Fprintf(os.Stderr, "  godeps size /usr/local/bin/kubectl\n\n")
+			return fmt.Errorf("missing arguments")
+		}
+		return nil
+	}
+
+	// Configure gomod command
+	gomodCmd.SilenceErrors = true
+	gomodCmd.SilenceUsage = true
+
+	gomodCmd.Args = nil
+	gomodCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			errorColor.Fprintf(os.Stderr, "❌ Error: gomod命令需要一个二进制文件路径参数\n\n")
+			warnColor.Fprintf(os.Stderr, "正确用法：\n")
+			fmt.Fprintf(os.Stderr, "  godeps gomod <go-binary-file> [-o <dir>]\n\n")
+			fmt.Fprintf(os.Stderr, "例如：\n")
+			fmt.Fprintf(os.Stderr, "  godeps gomod /usr/local/bin/kubectl -o ./kubectl-src\n\n")
+			return fmt.Errorf("missing arguments")
+		}
+		return nil
+	}
+
+	// Configure rebuild-cmd command
+	rebuildCmd.SilenceErrors = true
+	rebuildCmd.SilenceUsage = true
+
+	rebuildCmd.Args = nil
+	rebuildCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			errorColor.Fprintf(os.Stderr, "❌ Error: rebuild-cmd命令需要一个二进制文件路径参数\n\n")
+			warnColor.Fprintf(os.Stderr, "正确用法：\n")
+			fmt.Fprintf(os.Stderr, "  godeps rebuild-cmd <go-binary-file>\n\n")
+			fmt.Fprintf(os.Stderr, "例如：\n")
+			fmt.Fprintf(os.Stderr, "  godeps rebuild-cmd /usr/local/bin/kubectl > rebuild.sh\n\n")
+			return fmt.Errorf("missing arguments")
+		}
+		return nil
+	}
+
+	// Configure paths command
+	pathsCmd.SilenceErrors = true
+	pathsCmd.SilenceUsage = true
+
+	pathsCmd.Args = nil
+	pathsCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			errorColor.Fprintf(os.Stderr, "❌ Error: paths命令需要一个二进制文件路径参数\n\n")
+			warnColor.Fprintf(os.Stderr, "正确用法：\n")
+			fmt.Fprintf(os.Stderr, "  godeps paths [--leaks] <go-binary-file>\n\n")
+			fmt.Fprintf(os.Stderr, "例如：\n")
+			fmt.Fprintf(os.Stderr, "  godeps paths --leaks /usr/local/bin/app\n\n")
+			return fmt.Errorf("missing arguments")
+		}
+		return nil
+	}
+
+	// Configure vars command
+	varsCmd.SilenceErrors = true
+	varsCmd.SilenceUsage = true
+
+	varsCmd.Args = nil
+	varsCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			errorColor.Fprintf(os.Stderr, "❌ Error: vars命令需要一个二进制文件路径参数\n\n")
+			warnColor.Fprintf(os.Stderr, "正确用法：\n")
+			fmt.Fprintf(os.Stderr, "  godeps vars <go-binary-file> [variable...]\n\n")
+			fmt.Fprintf(os.Stderr, "例如：\n")
+			fmt.Fprintf(os.Stderr, "  godeps vars /usr/local/bin/app main.version main.commit\n\n")
+			return fmt.Errorf("missing arguments")
+		}
+		return nil
+	}
 }
 
 // printCustomHelp prints a custom help message with color
@@ -107,7 +197,7 @@ func printCustomHelp() {
 
 	// Print usage directly
 	subHeaderColor.Println("Usage:")
-	highlightColor.Println("  godeps [flags] <go-binary-file>")
+	highlightColor.Println("  godeps [flags] <go-binary-file | url>")
 	highlightColor.Println("  godeps [command]")
 	fmt.Println()
 
@@ -116,23 +206,43 @@ func printCustomHelp() {
 	fmt.Println("Generate the autocompletion script for the specified shell")
 	moduleColor.Print("  find        ")
 	fmt.Println("Find a specific dependency in a Go binary file")
+	moduleColor.Print("  gomod       ")
+	fmt.Println("Reconstruct go.mod and go.sum from a Go binary file")
 	moduleColor.Print("  help        ")
 	fmt.Println("Help about any command")
+	moduleColor.Print("  paths       ")
+	fmt.Println("List the source file paths recorded in a Go binary")
+	moduleColor.Print("  rebuild-cmd ")
+	fmt.Println("Print a shell script that rebuilds a Go binary file")
+	moduleColor.Print("  size        ")
+	fmt.Println("Show how much of the binary size each module accounts for")
 	moduleColor.Print("  stdlib      ")
 	fmt.Println("Show only standard library dependencies")
+	moduleColor.Print("  vars        ")
+	fmt.Println("Print the string variables set with -ldflags -X")
 	fmt.Println()
 
 	subHeaderColor.Println("Flags:")
-	highlightColor.Print("  -h, --help       ")
+	highlightColor.Print("  -h, --help          ")
 	fmt.Println("help for godeps")
-	highlightColor.Print("  -j, --json       ")
+	highlightColor.Print("  -j, --json          ")
 	fmt.Println("Output in JSON format")
-	highlightColor.Print("  -s, --nostdlib   ")
+	highlightColor.Print("  -s, --nostdlib      ")
 	fmt.Println("Filter out standard library dependencies")
-	highlightColor.Print("  -r, --replaced   ")
+	highlightColor.Print("  -r, --replaced      ")
 	fmt.Println("Only show dependencies that have been replaced")
-	highlightColor.Print("  -v, --verbose    ")
+	highlightColor.Print("  -v, --verbose       ")
 	fmt.Println("Show detailed information including checksums")
+	highlightColor.Print("      --deep          ")
+	fmt.Println("Search the whole file for embedded Go binaries")
+	highlightColor.Print("      --effective     ")
+	fmt.Println("Show the module that was actually linked instead of the requested one")
+	highlightColor.Print("      --go-version    ")
+	fmt.Println("Only accept binaries whose Go version satisfies the constraint")
+	highlightColor.Print("      --max-download  ")
+	fmt.Println("Maximum size of a binary downloaded from a URL (e.g. 512MB)")
+	highlightColor.Print("      --no-progress   ")
+	fmt.Println("Do not show a progress bar when downloading from a URL")
 
 	// Show examples
 	fmt.Println()
@@ -141,8 +251,20 @@ func printCustomHelp() {
 	fmt.Println("# Analyze a binary")
 	successColor.Print("  godeps -v -j /usr/local/bin/docker         ")
 	fmt.Println("# Verbose JSON output")
+	successColor.Print("  godeps https://example.com/bin/app         ")
+	fmt.Println("# Download and analyze a binary")
 	successColor.Print("  godeps find cobra /usr/local/bin/kubectl   ")
 	fmt.Println("# Find specific dependency")
 	successColor.Print("  godeps stdlib /usr/local/bin/go            ")
 	fmt.Println("# Show standard library dependencies")
+	successColor.Print("  godeps size -n 20 /usr/local/bin/kubectl   ")
+	fmt.Println("# Show the 20 largest modules by size")
+	successColor.Print("  godeps gomod -o ./src /usr/local/bin/app   ")
+	fmt.Println("# Reconstruct go.mod and go.sum")
+	successColor.Print("  godeps rebuild-cmd /usr/local/bin/app      ")
+	fmt.Println("# Print the go build invocation")
+	successColor.Print("  godeps paths --leaks /usr/local/bin/app    ")
+	fmt.Println("# Check source paths for leaked usernames")
+	successColor.Print("  godeps vars /usr/local/bin/app             ")
+	fmt.Println("# Print a variable set with -ldflags -X")
 }
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/fatih/color"
 	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
 )
 
@@ -15,16 +19,65 @@ func printInfo(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 	headerColor.Println("📦 Go Binary Dependency Analysis")
 	fmt.Println()
 
+	if info.Degraded != nil {
+		warnColor.Printf("⚠️  No build info found (%s)\n", info.Degraded.Reason)
+		warnColor.Println("⚠️  Go version and modules were recovered heuristically from the symbol table and pclntab")
+		fmt.Println()
+	}
+
 	subHeaderColor.Print("Binary: ")
 	fmt.Println(info.FilePath)
 
 	subHeaderColor.Print("Main module: ")
-	moduleColor.Printf("%s", info.Path)
-	fmt.Print("@")
-	versionColor.Println(info.Version)
+	if info.Path == "" && info.Degraded != nil {
+		warnColor.Println("unknown")
+	} else {
+		moduleColor.Printf("%s", info.Path)
+		fmt.Print("@")
+		versionColor.Println(info.Version)
+	}
 
 	subHeaderColor.Print("Go version: ")
-	successColor.Println(info.GoVersion)
+	if info.Degraded != nil {
+		goVersion := info.GoVersion
+		if goVersion == "" {
+			goVersion = "unknown"
+		}
+		successColor.Print(goVersion)
+		if info.Degraded.GoVersionSource != "" {
+			fmt.Printf(" (from %s, ", info.Degraded.GoVersionSource)
+			confidenceColor(info.Degraded.GoVersionConfidence).Print(info.Degraded.GoVersionConfidence)
+			fmt.Print(" confidence)")
+		}
+		fmt.Println()
+	} else {
+		successColor.Println(info.GoVersion)
+	}
+
+	if info.Format != nil {
+		subHeaderColor.Print("Format: ")
+		fmt.Println(formatSummary(info.Format))
+	}
+
+	if info.BuildMode != "" {
+		subHeaderColor.Print("Build mode: ")
+		highlightColor.Print(info.BuildMode)
+		if info.ArchiveMember != "" {
+			fmt.Printf(" (archive member %s)", info.ArchiveMember)
+		}
+		fmt.Println()
+	}
+
+	if verboseFlag && info.BuildID != nil {
+		subHeaderColor.Print("Build ID: ")
+		fmt.Println(info.BuildID.ID)
+		if info.BuildID.ActionID != "" {
+			subHeaderColor.Print("  Action ID: ")
+			highlightColor.Println(info.BuildID.ActionID)
+			subHeaderColor.Print("  Content ID: ")
+			highlightColor.Println(info.BuildID.ContentID)
+		}
+	}
 
 	// Print build settings if verbose
 	if verboseFlag && len(info.BuildSettings) > 0 {
@@ -36,6 +89,16 @@ func printInfo(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 		}
 	}
 
+	// Print structured build configuration if verbose
+	if verboseFlag && info.BuildConfig != nil {
+		printBuildConfig(info.BuildConfig)
+	}
+
+	// Print executable format details if verbose
+	if verboseFlag && info.Format != nil {
+		printFormat(info.Format)
+	}
+
 	// Print dependencies
 	fmt.Println()
 	subHeaderColor.Print("Dependencies ")
@@ -45,62 +108,293 @@ func printInfo(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 	// Use a tabwriter for aligned output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-	if verboseFlag {
-		tableHeaderColor.Fprintln(w, "  MODULE\tVERSION\tSUM\tREPLACED BY")
+	if info.Degraded != nil {
+		// Recovered modules have no checksums or replacements, show how reliable each one is instead
+		tableHeaderColor.Fprintln(w, "  MODULE\tVERSION\tCONFIDENCE")
 		for _, dep := range deps {
-			replacedBy := ""
-			if dep.Replace != nil {
-				replacedBy = fmt.Sprintf("%s@%s", dep.Replace.Path, dep.Replace.Version)
-			}
-
-			// Print with colors
 			fmt.Fprint(w, "  ")
 			moduleColor.Fprintf(w, "%s\t", dep.Path)
 			versionColor.Fprintf(w, "%s\t", dep.Version)
-			fmt.Fprintf(w, "%s\t", dep.Sum)
+			confidenceColor(dep.Confidence).Fprintln(w, dep.Confidence)
+		}
+	} else if verboseFlag {
+		tableHeaderColor.Fprintf(w, "  MODULE\tVERSION\tSUM\t%s\n", replaceColumnHeader())
+		for _, dep := range deps {
+			row := newDependencyRow(dep)
 
-			if replacedBy != "" {
-				replacedColor.Fprintf(w, "%s", replacedBy)
+			// Print with colors
+			fmt.Fprint(w, "  ")
+			moduleColor.Fprintf(w, "%s\t", row.path)
+			versionColor.Fprintf(w, "%s\t", row.version)
+			fmt.Fprintf(w, "%s\t", row.sum)
+
+			if row.replace != "" {
+				replacedColor.Fprintf(w, "%s", row.replace)
 			}
 			fmt.Fprintln(w)
 		}
 	} else {
-		tableHeaderColor.Fprintln(w, "  MODULE\tVERSION\tREPLACED BY")
+		tableHeaderColor.Fprintf(w, "  MODULE\tVERSION\t%s\n", replaceColumnHeader())
 		for _, dep := range deps {
-			replacedBy := ""
-			if dep.Replace != nil {
-				replacedBy = fmt.Sprintf("%s@%s", dep.Replace.Path, dep.Replace.Version)
-			}
+			row := newDependencyRow(dep)
 
 			// Print with colors
 			fmt.Fprint(w, "  ")
-			moduleColor.Fprintf(w, "%s\t", dep.Path)
-			versionColor.Fprintf(w, "%s\t", dep.Version)
+			moduleColor.Fprintf(w, "%s\t", row.path)
+			versionColor.Fprintf(w, "%s\t", row.version)
 
-			if replacedBy != "" {
-				replacedColor.Fprintf(w, "%s", replacedBy)
+			if row.replace != "" {
+				replacedColor.Fprintf(w, "%s", row.replace)
 			}
 			fmt.Fprintln(w)
 		}
 	}
 
 	w.Flush()
+
+	if len(info.Nested) > 0 {
+		fmt.Println()
+		subHeaderColor.Print("Nested Go binaries ")
+		highlightColor.Printf("(%d)", countNested(info.Nested))
+		subHeaderColor.Println(":")
+		printNested(info.Nested, "  ")
+	}
+}
+
+// printNested prints the Go binaries embedded in a binary as an indented tree
+func printNested(nested []gobinaryparser.NestedBinary, indent string) {
+	for _, n := range nested {
+		fmt.Print(indent)
+		highlightColor.Printf("@0x%x", n.Offset)
+		fmt.Print("  ")
+		if n.Info.Path != "" {
+			moduleColor.Print(n.Info.Path)
+			fmt.Print("@")
+			versionColor.Print(n.Info.Version)
+		} else {
+			warnColor.Print("unknown")
+		}
+		fmt.Printf("  %s, %d dependencies", n.Info.GoVersion, len(n.Info.Dependencies))
+		switch {
+		case !n.HeaderFound:
+			warnColor.Print("  (build info only)")
+		case n.Info.Format != nil:
+			fmt.Printf("  %s, %s", formatSummary(n.Info.Format), formatBytes(uint64(n.Size)))
+		}
+		fmt.Println()
+		printNested(n.Info.Nested, indent+"  ")
+	}
+}
+
+// countNested returns the number of embedded Go binaries at any depth
+func countNested(nested []gobinaryparser.NestedBinary) int {
+	count := len(nested)
+	for _, n := range nested {
+		count += countNested(n.Info.Nested)
+	}
+	return count
+}
+
+// dependencyRow holds the columns of a dependency table row
+type dependencyRow struct {
+	path    string
+	version string
+	sum     string
+	replace string
+}
+
+// newDependencyRow builds the table row for a dependency. With --effective the row shows the
+// module that was actually linked and the last column names the requested module it replaces;
+// otherwise it shows the requested module and the last column names its replacement.
+func newDependencyRow(dep gobinaryparser.DependencyInfo) dependencyRow {
+	if !effectiveFlag {
+		row := dependencyRow{path: dep.Path, version: dep.Version, sum: dep.Sum}
+		if dep.Replace != nil {
+			row.replace = formatModule(dep.Replace.Path, dep.Replace.Version)
+		}
+		return row
+	}
+
+	m := dep.Effective()
+	row := dependencyRow{path: m.Path, version: m.Version, sum: m.Sum}
+	if m.Requested != nil {
+		row.replace = fmt.Sprintf("%s (%s)", formatModule(m.Requested.Path, m.Requested.Version), m.ReplaceKind)
+	}
+	return row
+}
+
+// replaceColumnHeader returns the header of the last column of a dependency table
+func replaceColumnHeader() string {
+	if effectiveFlag {
+		return "REPLACES"
+	}
+	return "REPLACED BY"
+}
+
+// formatModule formats a module as path@version, or just the path for local directories
+func formatModule(path, version string) string {
+	if version == "" {
+		return path
+	}
+	return path + "@" + version
+}
+
+// confidenceColor returns the color used to display a confidence level
+func confidenceColor(confidence gobinaryparser.Confidence) *color.Color {
+	switch confidence {
+	case gobinaryparser.ConfidenceHigh:
+		return successColor
+	case gobinaryparser.ConfidenceMedium:
+		return highlightColor
+	}
+	return warnColor
+}
+
+// printBuildConfig prints the structured build configuration
+func printBuildConfig(config *gobinaryparser.BuildConfig) {
+	fmt.Println()
+	subHeaderColor.Println("Build Config:")
+
+	printField := func(name string, value string) {
+		if value == "" {
+			return
+		}
+		fmt.Printf("  %-14s ", name+":")
+		highlightColor.Println(value)
+	}
+
+	printField("Platform", strings.Trim(config.GOOS+"/"+config.GOARCH, "/"))
+	for _, key := range sortedKeys(config.ArchFeatures) {
+		printField(key, config.ArchFeatures[key])
+	}
+	printField("Compiler", config.Compiler)
+	printField("Build mode", config.BuildMode)
+	printField("CGO enabled", fmt.Sprintf("%t", config.CGOEnabled))
+	printField("Trimpath", fmt.Sprintf("%t", config.TrimPath))
+	printField("Tags", strings.Join(config.Tags, ","))
+	printField("Ldflags", strings.Join(config.LDFlags, " "))
+	printField("Gcflags", strings.Join(config.GCFlags, " "))
+	printField("Asmflags", strings.Join(config.ASMFlags, " "))
+	printField("PGO", config.PGO)
+	printField("Experiments", strings.Join(config.Experiments, ","))
+
+	if config.VCS != nil {
+		printField("VCS", config.VCS.System)
+		printField("Revision", config.VCS.Revision)
+		if !config.VCS.Time.IsZero() {
+			printField("Commit time", config.VCS.Time.Format(time.RFC3339))
+		}
+		if config.VCS.Modified {
+			fmt.Printf("  %-14s ", "Modified:")
+			warnColor.Println("true (dirty working tree)")
+		} else {
+			printField("Modified", "false")
+		}
+	}
+}
+
+// formatSummary returns a one-line description of the executable format
+func formatSummary(format *gobinaryparser.FormatInfo) string {
+	linking := "dynamic"
+	if format.Static {
+		linking = "static"
+	}
+	summary := fmt.Sprintf("%s/%s (%d-bit, %s-endian, %s", format.Type, format.Arch, format.Bits, format.Endian, linking)
+	if format.PIE {
+		summary += ", PIE"
+	}
+	if format.Shared {
+		summary += ", shared library"
+	}
+	return summary + ")"
+}
+
+// printFormat prints the executable format details
+func printFormat(format *gobinaryparser.FormatInfo) {
+	fmt.Println()
+	subHeaderColor.Println("Executable Format:")
+
+	fmt.Printf("  %-14s ", "Symbols:")
+	if format.SymbolsStripped {
+		warnColor.Println("stripped")
+	} else {
+		highlightColor.Println("present")
+	}
+	fmt.Printf("  %-14s ", "DWARF:")
+	if format.DWARFStripped {
+		warnColor.Println("stripped")
+	} else {
+		highlightColor.Println("present")
+	}
+	if format.Interpreter != "" {
+		fmt.Printf("  %-14s ", "Interpreter:")
+		highlightColor.Println(format.Interpreter)
+	}
+	if len(format.Libraries) > 0 {
+		fmt.Println("  Libraries:")
+		for _, lib := range format.Libraries {
+			fmt.Print("    ├─ ")
+			highlightColor.Println(lib)
+		}
+	}
+}
+
+// formatBytes formats a byte count in a human-readable form
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// sortedKeys returns the keys of a string map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // printJSON prints the information in JSON format
 func printJSON(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.DependencyInfo) {
+	writeJSON(jsonOutput(info, deps))
+}
+
+// writeJSON encodes v as indented JSON and prints it
+func writeJSON(v interface{}) {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		errorColor.Fprintf(os.Stderr, "Error encoding to JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(jsonData))
+}
+
+// jsonOutput builds the JSON representation of a binary and its selected dependencies
+func jsonOutput(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.DependencyInfo) interface{} {
 	// Create a struct to hold the JSON data
 	type ReplaceInfo struct {
-		Path    string `json:"path"`
-		Version string `json:"version"`
-		Sum     string `json:"sum,omitempty"`
+		Path    string                     `json:"path"`
+		Version string                     `json:"version"`
+		Sum     string                     `json:"sum,omitempty"`
+		Kind    gobinaryparser.ReplaceKind `json:"kind"`
 	}
 
 	type DependencyOutput struct {
-		Path    string       `json:"path"`
-		Version string       `json:"version"`
-		Sum     string       `json:"sum,omitempty"`
-		Replace *ReplaceInfo `json:"replace,omitempty"`
+		Path       string                    `json:"path"`
+		Version    string                    `json:"version"`
+		Sum        string                    `json:"sum,omitempty"`
+		Replace    *ReplaceInfo              `json:"replace,omitempty"`
+		Confidence gobinaryparser.Confidence `json:"confidence,omitempty"`
 	}
 
 	type MainModule struct {
@@ -109,11 +403,19 @@ func printJSON(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 	}
 
 	type Output struct {
-		Binary        string             `json:"binary"`
-		Main          MainModule         `json:"main"`
-		GoVersion     string             `json:"goVersion"`
-		BuildSettings map[string]string  `json:"buildSettings,omitempty"`
-		Dependencies  []DependencyOutput `json:"dependencies"`
+		Binary        string                        `json:"binary"`
+		Main          MainModule                    `json:"main"`
+		GoVersion     string                        `json:"goVersion"`
+		BuildSettings map[string]string             `json:"buildSettings,omitempty"`
+		BuildConfig   *gobinaryparser.BuildConfig   `json:"buildConfig,omitempty"`
+		Format        *gobinaryparser.FormatInfo    `json:"format,omitempty"`
+		BuildID       *gobinaryparser.GoBuildID     `json:"buildId,omitempty"`
+		Degraded      *gobinaryparser.DegradedInfo  `json:"degraded,omitempty"`
+		BuildMode     string                        `json:"buildMode,omitempty"`
+		ArchiveMember string                        `json:"archiveMember,omitempty"`
+		Dependencies  []DependencyOutput            `json:"dependencies"`
+		Nested        []gobinaryparser.NestedBinary `json:"nested,omitempty"`
+		RawBuildInfo  *gobinaryparser.RawBuildInfo  `json:"rawBuildInfo,omitempty"`
 	}
 
 	// Create the output data
@@ -123,19 +425,28 @@ func printJSON(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 			Path:    info.Path,
 			Version: info.Version,
 		},
-		GoVersion:    info.GoVersion,
-		Dependencies: make([]DependencyOutput, 0, len(deps)),
+		GoVersion:     info.GoVersion,
+		BuildConfig:   info.BuildConfig,
+		Format:        info.Format,
+		BuildID:       info.BuildID,
+		Degraded:      info.Degraded,
+		BuildMode:     info.BuildMode,
+		ArchiveMember: info.ArchiveMember,
+		Dependencies:  make([]DependencyOutput, 0, len(deps)),
+		Nested:        info.Nested,
 	}
 
 	if verboseFlag {
 		output.BuildSettings = info.BuildSettings
+		output.RawBuildInfo = info.RawBuildInfo
 	}
 
 	// Add dependencies
 	for _, dep := range deps {
 		depOutput := DependencyOutput{
-			Path:    dep.Path,
-			Version: dep.Version,
+			Path:       dep.Path,
+			Version:    dep.Version,
+			Confidence: dep.Confidence,
 		}
 
 		if verboseFlag {
@@ -146,6 +457,7 @@ func printJSON(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 			replaceInfo := &ReplaceInfo{
 				Path:    dep.Replace.Path,
 				Version: dep.Replace.Version,
+				Kind:    dep.ReplaceKind(),
 			}
 
 			if verboseFlag {
@@ -158,13 +470,100 @@ func printJSON(info *gobinaryparser.BinaryInfo, deps []gobinaryparser.Dependency
 		output.Dependencies = append(output.Dependencies, depOutput)
 	}
 
-	// Marshal to JSON
-	jsonData, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		errorColor.Fprintf(os.Stderr, "Error encoding to JSON: %v\n", err)
-		os.Exit(1)
+	return output
+}
+
+// printUniversalInfo prints every architecture slice of a universal binary separately
+func printUniversalInfo(universal *gobinaryparser.UniversalBinaryInfo) {
+	headerColor.Println("🍎 Mach-O Universal Binary")
+	fmt.Println()
+
+	subHeaderColor.Print("Binary: ")
+	fmt.Println(universal.FilePath)
+
+	archs := make([]string, 0, len(universal.Slices))
+	for _, slice := range universal.Slices {
+		archs = append(archs, slice.Arch)
 	}
+	subHeaderColor.Print("Architectures: ")
+	highlightColor.Println(strings.Join(archs, ", "))
 
-	// Print the JSON
-	fmt.Println(string(jsonData))
+	subHeaderColor.Print("Consistent: ")
+	if universal.Consistent {
+		successColor.Println("yes")
+	} else {
+		warnColor.Printf("no (%d differences)\n", len(universal.Mismatches))
+		for _, mismatch := range universal.Mismatches {
+			fmt.Print("  ⚠️  ")
+			if mismatch.Path != "" {
+				moduleColor.Printf("%s ", mismatch.Path)
+			} else {
+				fmt.Printf("%s ", mismatch.Field)
+			}
+			values := make([]string, 0, len(archs))
+			for _, arch := range archs {
+				value, ok := mismatch.Values[arch]
+				if !ok {
+					continue
+				}
+				if value == "" {
+					value = "(missing)"
+				}
+				values = append(values, arch+"="+value)
+			}
+			fmt.Println(strings.Join(values, ", "))
+		}
+	}
+
+	for _, slice := range universal.Slices {
+		fmt.Println()
+		headerColor.Printf("━━━ %s slice (offset %d, %s) ━━━\n", slice.Arch, slice.Offset, formatBytes(slice.Size))
+		if slice.Info == nil {
+			errorColor.Printf("Error parsing slice: %s\n", slice.Error)
+			continue
+		}
+		fmt.Println()
+		printInfo(slice.Info, selectDependencies(slice.Info))
+	}
+}
+
+// printUniversalJSON prints every architecture slice of a universal binary in JSON format
+func printUniversalJSON(universal *gobinaryparser.UniversalBinaryInfo) {
+	type SliceOutput struct {
+		Arch   string      `json:"arch"`
+		Offset uint64      `json:"offset"`
+		Size   uint64      `json:"size"`
+		Error  string      `json:"error,omitempty"`
+		Result interface{} `json:"result,omitempty"`
+	}
+
+	type Output struct {
+		Binary     string                         `json:"binary"`
+		Universal  bool                           `json:"universal"`
+		Consistent bool                           `json:"consistent"`
+		Mismatches []gobinaryparser.SliceMismatch `json:"mismatches,omitempty"`
+		Slices     []SliceOutput                  `json:"slices"`
+	}
+
+	output := Output{
+		Binary:     universal.FilePath,
+		Universal:  true,
+		Consistent: universal.Consistent,
+		Mismatches: universal.Mismatches,
+		Slices:     make([]SliceOutput, 0, len(universal.Slices)),
+	}
+	for _, slice := range universal.Slices {
+		sliceOutput := SliceOutput{
+			Arch:   slice.Arch,
+			Offset: slice.Offset,
+			Size:   slice.Size,
+			Error:  slice.Error,
+		}
+		if slice.Info != nil {
+			sliceOutput.Result = jsonOutput(slice.Info, selectDependencies(slice.Info))
+		}
+		output.Slices = append(output.Slices, sliceOutput)
+	}
+
+	writeJSON(output)
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	gobinaryparser "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+)
+
+// progressBarWidth is the number of cells in the download progress bar
+const progressBarWidth = 30
+
+// isURL reports whether the argument is an HTTP or HTTPS URL rather than a file path
+func isURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// isTerminal reports whether f is connected to a terminal
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// newProgressBar returns a progress callback that draws a download progress bar on stderr
+func newProgressBar() gobinaryparser.ProgressFunc {
+	return func(p gobinaryparser.DownloadProgress) {
+		rate := formatBytes(uint64(p.Rate)) + "/s"
+		if percent := p.Percent(); percent >= 0 {
+			filled := int(percent / 100 * progressBarWidth)
+			bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+			fmt.Fprintf(os.Stderr, "\r⬇️  %s %3.0f%%  %s / %s  %s   ", bar, percent,
+				formatBytes(uint64(p.BytesRead)), formatBytes(uint64(p.Total)), rate)
+		} else {
+			fmt.Fprintf(os.Stderr, "\r⬇️  %s  %s   ", formatBytes(uint64(p.BytesRead)), rate)
+		}
+		if p.Done {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+}
+
+// parseByteSize parses a size such as "512MB", "2GiB", "64k" or "1048576" into bytes.
+// Units are powers of 1024.
+func parseByteSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+
+	multiplier := int64(1)
+	if n := len(value); n > 0 {
+		if i := strings.IndexByte("KMGT", value[n-1]); i >= 0 {
+			multiplier = int64(1) << (10 * (i + 1))
+			value = value[:n-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return n * multiplier, nil
+}
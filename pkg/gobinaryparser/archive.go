@@ -0,0 +1,266 @@
+package gobinaryparser
+
+import (
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// ar静态库的文件格式常量
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+// buildInfoMagic 是链接器写入的构建信息头部的魔数
+var buildInfoMagic = []byte("\xff Go buildinf:")
+
+// buildInfoHeaderSize 是构建信息头部的字节数：魔数、指针大小、标志位以及对齐填充
+const buildInfoHeaderSize = 32
+
+// archiveMember 表示ar静态库中的一个成员文件
+type archiveMember struct {
+	Name   string
+	Offset int64 // 成员数据在静态库中的偏移
+	Size   int64 // 成员数据的字节数
+}
+
+// isArchive 判断数据是否为ar静态库（例如 -buildmode=c-archive 生成的 .a 文件）
+func isArchive(r io.ReaderAt) bool {
+	magic := make([]byte, len(arMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		return false
+	}
+	return string(magic) == arMagic
+}
+
+// readArchiveMembers 读取ar静态库的成员列表，支持GNU和BSD两种长文件名格式，跳过符号表成员
+func readArchiveMembers(r io.ReaderAt) ([]archiveMember, error) {
+	var members []archiveMember
+	var longNames []byte
+
+	offset := int64(len(arMagic))
+	header := make([]byte, arHeaderSize)
+	for {
+		n, err := r.ReadAt(header, offset)
+		if n == 0 && err == io.EOF {
+			break
+		}
+		if n < arHeaderSize {
+			// 文件末尾可能有用于对齐的换行符
+			if strings.TrimSpace(string(header[:n])) == "" {
+				break
+			}
+			return nil, fmt.Errorf("静态库成员头不完整 (偏移 %d)", offset)
+		}
+		if string(header[58:60]) != "`\n" {
+			return nil, fmt.Errorf("静态库成员头格式错误 (偏移 %d)", offset)
+		}
+
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("静态库成员大小无效 (偏移 %d)", offset)
+		}
+		next := offset + arHeaderSize + size + size&1
+
+		member := archiveMember{
+			Name:   strings.TrimRight(string(header[:16]), " "),
+			Offset: offset + arHeaderSize,
+			Size:   size,
+		}
+
+		switch {
+		case member.Name == "//":
+			// GNU格式的长文件名表
+			longNames = make([]byte, size)
+			if _, err := r.ReadAt(longNames, member.Offset); err != nil {
+				return nil, fmt.Errorf("读取静态库长文件名表失败: %w", err)
+			}
+			offset = next
+			continue
+		case strings.HasPrefix(member.Name, "#1/"):
+			// BSD格式：文件名紧跟在成员头之后，长度记录在 #1/ 之后
+			nameLen, err := strconv.ParseInt(member.Name[3:], 10, 64)
+			if err != nil || nameLen < 0 || nameLen > size {
+				return nil, fmt.Errorf("静态库成员名长度无效 (偏移 %d)", offset)
+			}
+			name := make([]byte, nameLen)
+			if _, err := r.ReadAt(name, member.Offset); err != nil {
+				return nil, fmt.Errorf("读取静态库成员名失败: %w", err)
+			}
+			member.Name = strings.TrimRight(string(name), "\x00")
+			member.Offset += nameLen
+			member.Size -= nameLen
+		case len(member.Name) > 1 && member.Name[0] == '/' && member.Name[1] >= '0' && member.Name[1] <= '9':
+			// GNU格式：/<偏移> 引用长文件名表中以 "/\n" 结尾的文件名
+			index, err := strconv.Atoi(member.Name[1:])
+			if err != nil || index >= len(longNames) {
+				return nil, fmt.Errorf("静态库长文件名引用无效: %s", member.Name)
+			}
+			name := longNames[index:]
+			if end := bytes.Index(name, []byte("/\n")); end >= 0 {
+				name = name[:end]
+			}
+			member.Name = string(name)
+		default:
+			member.Name = strings.TrimSuffix(member.Name, "/")
+		}
+
+		// 符号表成员：GNU格式为 "/" 和 "/SYM64/"，BSD格式为 "__.SYMDEF" 系列
+		if member.Name != "" && member.Name != "/SYM64" && !strings.HasPrefix(member.Name, "__.SYMDEF") {
+			members = append(members, member)
+		}
+		offset = next
+	}
+
+	return members, nil
+}
+
+// readArchiveBinaryInfo 在ar静态库的成员中查找Go构建信息。
+// -buildmode=c-archive 生成的静态库中，Go代码被链接为一个目标文件（通常名为 go.o），
+// 其中包含内联格式的构建信息。目标文件尚未重定位，因此只支持Go 1.18及以上版本使用的内联格式。
+func readArchiveBinaryInfo(r io.ReaderAt, path string, sourceType string) (*BinaryInfo, error) {
+	members, err := readArchiveMembers(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, member := range members {
+		sr := io.NewSectionReader(r, member.Offset, member.Size)
+		var info *buildinfo.BuildInfo
+		offset, err := findBuildInfo(sr, member.Size, buildInfoAlign, func(offset int64) bool {
+			var err error
+			if info, err = readInlineBuildInfo(sr, offset); err != nil {
+				lastErr = fmt.Errorf("静态库成员 %s: %w", member.Name, err)
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("读取静态库成员 %s 失败: %w", member.Name, err)
+		}
+		if offset < 0 {
+			continue
+		}
+
+		result, err := createBinaryInfo(info, sr, path, sourceType)
+		if err != nil {
+			return nil, err
+		}
+		result.ArchiveMember = member.Name
+		result.RawBuildInfo.shift(member.Offset)
+		if result.BuildMode == "" {
+			result.BuildMode = BuildModeCArchive
+		}
+		return result, nil
+	}
+
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%w: 静态库中没有包含Go构建信息的成员", ErrNotGoBinary)
+}
+
+// buildInfoAlign 是构建信息头部的对齐要求，相对于所在的节或静态库成员的开头
+const buildInfoAlign = 16
+
+// findBuildInfo 在数据中搜索构建信息头部，返回第一个按align对齐且accept返回true的位置，未找到时返回-1。
+// 链接了 debug/buildinfo 的程序以及-race构建的只读数据中也包含魔数字符串，但不会按16字节对齐，
+// 因此跳过未对齐的位置以及accept拒绝（例如无法解析）的位置继续搜索。accept为nil时接受所有对齐的位置。
+func findBuildInfo(r io.ReaderAt, size int64, align int64, accept func(offset int64) bool) (int64, error) {
+	const chunkSize = 1 << 20
+	overlap := int64(len(buildInfoMagic) - 1)
+
+	next := int64(0) // 尚未检查的最小位置，避免重复检查相邻块重叠部分中的匹配
+	buf := make([]byte, chunkSize)
+	for offset := int64(0); offset < size; offset += chunkSize - overlap {
+		n, err := r.ReadAt(buf, offset)
+		if n == 0 && err != nil && err != io.EOF {
+			return -1, err
+		}
+		chunk := buf[:n]
+		for i := 0; ; {
+			j := bytes.Index(chunk[i:], buildInfoMagic)
+			if j < 0 {
+				break
+			}
+			pos := offset + int64(i+j)
+			i += j + 1
+			if pos < next {
+				continue
+			}
+			next = pos + 1
+			if pos%align == 0 && (accept == nil || accept(pos)) {
+				return pos, nil
+			}
+		}
+		if int64(n) < chunkSize {
+			break
+		}
+	}
+	return -1, nil
+}
+
+// readInlineBuildInfo 解码offset处的内联格式构建信息。
+// 内联格式的头部之后依次是以uvarint长度为前缀的Go版本字符串和模块信息字符串。
+func readInlineBuildInfo(r io.ReaderAt, offset int64) (*buildinfo.BuildInfo, error) {
+	header := make([]byte, buildInfoHeaderSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, fmt.Errorf("读取构建信息头部失败: %w", err)
+	}
+	if !bytes.HasPrefix(header, buildInfoMagic) {
+		return nil, fmt.Errorf("构建信息魔数不匹配")
+	}
+	// 标志位的第2位表示版本和模块信息以内联方式存储，否则存储的是需要重定位的指针
+	if header[len(buildInfoMagic)+1]&2 == 0 {
+		return nil, fmt.Errorf("%w: 构建信息使用Go 1.18之前的指针格式，无法在未链接的目标文件中读取", ErrNoBuildInfo)
+	}
+
+	br := bufio.NewReader(io.NewSectionReader(r, offset+buildInfoHeaderSize, math.MaxInt64-offset-buildInfoHeaderSize))
+	vers, err := readVarintString(br)
+	if err != nil {
+		return nil, fmt.Errorf("读取Go版本失败: %w", err)
+	}
+	mod, err := readVarintString(br)
+	if err != nil {
+		return nil, fmt.Errorf("读取模块信息失败: %w", err)
+	}
+
+	// 模块信息前后各有16字节的哨兵值
+	if len(mod) >= 33 && mod[len(mod)-17] == '\n' {
+		mod = mod[16 : len(mod)-16]
+	} else {
+		mod = ""
+	}
+
+	info, err := debug.ParseBuildInfo(mod)
+	if err != nil {
+		return nil, fmt.Errorf("解析模块信息失败: %w", err)
+	}
+	info.GoVersion = vers
+	return info, nil
+}
+
+// readVarintString 读取以uvarint长度为前缀的字符串
+func readVarintString(br *bufio.Reader) (string, error) {
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return "", err
+	}
+	if length > 64<<20 {
+		return "", fmt.Errorf("字符串长度 %d 无效", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
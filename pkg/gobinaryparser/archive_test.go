@@ -0,0 +1,245 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// arMember 是构造测试用静态库时的成员
+type arMember struct {
+	name string
+	data []byte
+}
+
+// buildGNUArchive 构造GNU格式的静态库，包含符号表和长文件名表
+func buildGNUArchive(members []arMember) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(arMagic)
+
+	writeMember := func(name string, data []byte) {
+		fmt.Fprintf(&buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", name, 0, 0, 0, "644", len(data))
+		buf.Write(data)
+		if len(data)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+
+	var longNames bytes.Buffer
+	names := make([]string, len(members))
+	for i, m := range members {
+		if len(m.name) >= 16 {
+			names[i] = fmt.Sprintf("/%d", longNames.Len())
+			longNames.WriteString(m.name + "/\n")
+		} else {
+			names[i] = m.name + "/"
+		}
+	}
+
+	writeMember("/", []byte{0, 0, 0, 0})
+	if longNames.Len() > 0 {
+		writeMember("//", longNames.Bytes())
+	}
+	for i, m := range members {
+		writeMember(names[i], m.data)
+	}
+	return buf.Bytes()
+}
+
+// buildBSDArchive 构造BSD格式的静态库，文件名存储在成员数据之前
+func buildBSDArchive(members []arMember) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(arMagic)
+	for _, m := range append([]arMember{{"__.SYMDEF SORTED", []byte{0, 0, 0, 0}}}, members...) {
+		name := []byte(m.name)
+		for len(name)%8 != 0 {
+			name = append(name, 0)
+		}
+		fmt.Fprintf(&buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", fmt.Sprintf("#1/%d", len(name)), 0, 0, 0, "644", len(name)+len(m.data))
+		buf.Write(name)
+		buf.Write(m.data)
+		if (len(name)+len(m.data))%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestReadArchiveMembers(t *testing.T) {
+	members := []arMember{
+		{"short.o", []byte("odd")},
+		{"a_very_long_member_name.o", []byte("long name")},
+		{"go.o", []byte("go object")},
+	}
+
+	for name, archive := range map[string][]byte{
+		"gnu": buildGNUArchive(members),
+		"bsd": buildBSDArchive(members),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := bytes.NewReader(archive)
+			if !isArchive(r) {
+				t.Fatal("isArchive() = false, want true")
+			}
+
+			got, err := readArchiveMembers(r)
+			if err != nil {
+				t.Fatalf("readArchiveMembers() error = %v", err)
+			}
+			if len(got) != len(members) {
+				t.Fatalf("Expected %d members, got %+v", len(members), got)
+			}
+			for i, m := range members {
+				if got[i].Name != m.name {
+					t.Errorf("Member %d name = %q, want %q", i, got[i].Name, m.name)
+				}
+				data, _ := io.ReadAll(io.NewSectionReader(r, got[i].Offset, got[i].Size))
+				if string(data) != string(m.data) {
+					t.Errorf("Member %d data = %q, want %q", i, data, m.data)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBinaryFromBytes_Archive(t *testing.T) {
+	data, err := os.ReadFile(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test binary: %v", err)
+	}
+
+	archive := buildGNUArchive([]arMember{
+		{"000000.o", []byte("not a Go object")},
+		{"go.o", data},
+	})
+	info, err := ParseBinaryFromBytes(archive)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromBytes() error = %v", err)
+	}
+	if info.ArchiveMember != "go.o" {
+		t.Errorf("ArchiveMember = %q, want %q", info.ArchiveMember, "go.o")
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+}
+
+func TestParseBinaryFromBytes_ArchiveDecoyMagic(t *testing.T) {
+	data, err := os.ReadFile(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test binary: %v", err)
+	}
+	raw, err := ReadRawBuildInfo(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadRawBuildInfo() error = %v", err)
+	}
+	if raw.Format != RawFormatInline {
+		t.Skipf("Test binary uses the %s format", raw.Format)
+	}
+
+	// 未对齐的魔数字符串（例如链接了debug/buildinfo的程序中的字面量）和无法解析的对齐头部都应被跳过
+	var member bytes.Buffer
+	member.WriteString("lit")
+	member.Write(buildInfoMagic)
+	member.Write(make([]byte, 32-member.Len()))
+	member.Write(buildInfoMagic)
+	member.Write([]byte{8, 0}) // 指针格式，无法在目标文件中读取
+	member.Write(make([]byte, 64-member.Len()))
+	member.Write(data[raw.Offset:min(raw.Offset+4096, int64(len(data)))])
+
+	archive := buildGNUArchive([]arMember{{"go.o", member.Bytes()}})
+	info, err := ParseBinaryFromBytes(archive)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromBytes() error = %v", err)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+	members, _ := readArchiveMembers(bytes.NewReader(archive))
+	if want := members[0].Offset + 64; info.RawBuildInfo == nil || info.RawBuildInfo.Offset != want {
+		t.Errorf("RawBuildInfo = %+v, want offset %d", info.RawBuildInfo, want)
+	}
+
+	raw, err = ReadRawBuildInfo(bytes.NewReader(archive))
+	if err != nil || raw.Offset != members[0].Offset+64 {
+		t.Errorf("ReadRawBuildInfo() = %+v, %v, want offset %d", raw, err, members[0].Offset+64)
+	}
+}
+
+func TestParseBinaryFromBytes_ArchiveWithoutGo(t *testing.T) {
+	archive := buildGNUArchive([]arMember{{"foo.o", []byte("plain C object")}})
+	if _, err := ParseBinaryFromBytes(archive); err == nil {
+		t.Error("Expected error for an archive without Go build info")
+	}
+}
+
+func TestReadInlineBuildInfo_PointerFormat(t *testing.T) {
+	header := make([]byte, buildInfoHeaderSize)
+	copy(header, buildInfoMagic)
+	header[len(buildInfoMagic)] = 8
+	if _, err := readInlineBuildInfo(bytes.NewReader(header), 0); err == nil {
+		t.Error("Expected error for the pointer build info format")
+	}
+}
+
+func TestParseBinary_BuildModes(t *testing.T) {
+	const src = `package main
+
+import "C"
+
+//export Hello
+func Hello() {}
+
+func main() {}
+`
+	cgo := []string{"CGO_ENABLED=1"}
+	tests := []struct {
+		buildMode string
+		shared    bool
+	}{
+		{BuildModeCShared, true},
+		{BuildModePlugin, true},
+		{BuildModeCArchive, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.buildMode, func(t *testing.T) {
+			if runtime.GOOS != "linux" {
+				t.Skip("Build mode test only runs on linux")
+			}
+			path := buildTestProgram(t, src, cgo, "-buildmode="+tt.buildMode)
+
+			info, err := ParseBinary(path)
+			if err != nil {
+				t.Fatalf("ParseBinary() error = %v", err)
+			}
+			if info.BuildMode != tt.buildMode {
+				t.Errorf("BuildMode = %q, want %q", info.BuildMode, tt.buildMode)
+			}
+			if info.Path != "example.com/testprog" {
+				t.Errorf("Path = %q, want %q", info.Path, "example.com/testprog")
+			}
+			if info.Format == nil || info.Format.Shared != tt.shared {
+				t.Errorf("Format = %+v, want Shared = %t", info.Format, tt.shared)
+			}
+
+			// 去掉 -buildmode 设置后，共享库的构建模式根据文件结构推断
+			if tt.shared {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("Failed to open library: %v", err)
+				}
+				defer f.Close()
+				exe, err := openExecutable(f)
+				if err != nil {
+					t.Fatalf("openExecutable() error = %v", err)
+				}
+				if got := resolveBuildMode(nil, exe); got != tt.buildMode {
+					t.Errorf("Detected build mode = %q, want %q", got, tt.buildMode)
+				}
+			}
+		})
+	}
+}
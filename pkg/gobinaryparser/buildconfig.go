@@ -0,0 +1,132 @@
+package gobinaryparser
+
+import (
+	"strings"
+	"time"
+)
+
+// archFeatureKeys 是Go工具链记录的架构相关特性变量
+var archFeatureKeys = []string{
+	"GO386", "GOAMD64", "GOARM", "GOARM64", "GOMIPS", "GOMIPS64",
+	"GOPPC64", "GORISCV64", "GOWASM",
+}
+
+// cgoFlagKeys 是Go工具链在启用cgo时记录的编译标志变量
+var cgoFlagKeys = []string{
+	"CGO_CFLAGS", "CGO_CPPFLAGS", "CGO_CXXFLAGS", "CGO_LDFLAGS",
+}
+
+// NewBuildConfig 将原始的构建设置解析为结构化的BuildConfig。
+//
+// 参数:
+//   - settings: 构建设置键值对，通常为 BinaryInfo.BuildSettings
+//
+// 返回:
+//   - *BuildConfig: 解析后的编译配置，settings为空时返回零值配置
+//
+// 无法识别或格式错误的设置会被忽略，它们仍然可以通过原始的settings访问。
+//
+// 使用示例:
+//
+//	config := gobinaryparser.NewBuildConfig(info.BuildSettings)
+//	if config.VCS != nil && config.VCS.Modified {
+//		fmt.Println("二进制文件是从有未提交修改的工作区构建的")
+//	}
+//	fmt.Printf("构建标签: %v\n", config.Tags)
+func NewBuildConfig(settings map[string]string) *BuildConfig {
+	config := &BuildConfig{
+		GOOS:           settings["GOOS"],
+		GOARCH:         settings["GOARCH"],
+		Compiler:       settings["-compiler"],
+		BuildMode:      settings["-buildmode"],
+		CGOEnabled:     settings["CGO_ENABLED"] == "1",
+		Tags:           splitList(settings["-tags"], ","),
+		LDFlags:        splitQuotedArgs(settings["-ldflags"]),
+		GCFlags:        splitQuotedArgs(settings["-gcflags"]),
+		ASMFlags:       splitQuotedArgs(settings["-asmflags"]),
+		TrimPath:       settings["-trimpath"] == "true",
+		Race:           settings["-race"] == "true",
+		MSan:           settings["-msan"] == "true",
+		ASan:           settings["-asan"] == "true",
+		PGO:            settings["-pgo"],
+		Experiments:    splitList(settings["GOEXPERIMENT"], ","),
+		DefaultGODEBUG: settings["DefaultGODEBUG"],
+	}
+
+	for _, key := range archFeatureKeys {
+		if value, ok := settings[key]; ok {
+			if config.ArchFeatures == nil {
+				config.ArchFeatures = make(map[string]string)
+			}
+			config.ArchFeatures[key] = value
+		}
+	}
+
+	for _, key := range cgoFlagKeys {
+		if value, ok := settings[key]; ok {
+			if config.CGOFlags == nil {
+				config.CGOFlags = make(map[string]string)
+			}
+			config.CGOFlags[key] = value
+		}
+	}
+
+	if system, ok := settings["vcs"]; ok {
+		vcs := &VCSInfo{
+			System:   system,
+			Revision: settings["vcs.revision"],
+			Modified: settings["vcs.modified"] == "true",
+		}
+		if t, err := time.Parse(time.RFC3339Nano, settings["vcs.time"]); err == nil {
+			vcs.Time = t
+		}
+		config.VCS = vcs
+	}
+
+	return config
+}
+
+// splitList 按分隔符拆分列表类型的设置，并去除空元素
+func splitList(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitQuotedArgs 按照Go工具链的引号规则拆分参数字符串。
+// 参数之间以空白分隔，单引号或双引号包围的部分作为一个整体（不支持转义），
+// 这与 go build 记录 -ldflags 等设置时使用的格式一致。
+// 如果引号不匹配，则退化为按空白拆分。
+func splitQuotedArgs(value string) []string {
+	var args []string
+	s := value
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " \t\n\r")
+		if len(s) == 0 {
+			break
+		}
+
+		if s[0] == '"' || s[0] == '\'' {
+			quote := s[0]
+			end := strings.IndexByte(s[1:], quote)
+			if end < 0 {
+				return strings.Fields(value)
+			}
+			args = append(args, s[1:end+1])
+			s = s[end+2:]
+			continue
+		}
+
+		end := strings.IndexAny(s, " \t\n\r")
+		if end < 0 {
+			end = len(s)
+		}
+		args = append(args, s[:end])
+		s = s[end:]
+	}
+	return args
+}
@@ -0,0 +1,99 @@
+package gobinaryparser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewBuildConfig(t *testing.T) {
+	settings := map[string]string{
+		"-buildmode":   "pie",
+		"-compiler":    "gc",
+		"-tags":        "netgo,osusergo",
+		"-ldflags":     `-s -w -X main.version=v1.2.3 -X "main.commit=abc def"`,
+		"-gcflags":     "all=-N -l",
+		"-trimpath":    "true",
+		"-race":        "true",
+		"CGO_ENABLED":  "1",
+		"CGO_CFLAGS":   "-O2 -g",
+		"GOARCH":       "amd64",
+		"GOOS":         "linux",
+		"GOAMD64":      "v3",
+		"GOEXPERIMENT": "boringcrypto,loopvar",
+		"vcs":          "git",
+		"vcs.revision": "a7f686d8f418f7a3d4f8d2e0c1b5e6d7c8f9a0b1",
+		"vcs.time":     "2023-05-01T12:00:00Z",
+		"vcs.modified": "true",
+	}
+
+	config := NewBuildConfig(settings)
+
+	if config.GOOS != "linux" || config.GOARCH != "amd64" {
+		t.Errorf("Expected linux/amd64, got %s/%s", config.GOOS, config.GOARCH)
+	}
+	if config.ArchFeatures["GOAMD64"] != "v3" {
+		t.Errorf("Expected GOAMD64=v3, got %v", config.ArchFeatures)
+	}
+	if config.BuildMode != "pie" || config.Compiler != "gc" {
+		t.Errorf("Unexpected build mode or compiler: %s, %s", config.BuildMode, config.Compiler)
+	}
+	if !config.CGOEnabled || !config.TrimPath || !config.Race {
+		t.Errorf("Expected cgo, trimpath and race to be enabled: %+v", config)
+	}
+	if config.CGOFlags["CGO_CFLAGS"] != "-O2 -g" {
+		t.Errorf("Expected CGO_CFLAGS to be recorded, got %v", config.CGOFlags)
+	}
+	if want := []string{"netgo", "osusergo"}; !reflect.DeepEqual(config.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, config.Tags)
+	}
+	wantLDFlags := []string{"-s", "-w", "-X", "main.version=v1.2.3", "-X", "main.commit=abc def"}
+	if !reflect.DeepEqual(config.LDFlags, wantLDFlags) {
+		t.Errorf("Expected ldflags %q, got %q", wantLDFlags, config.LDFlags)
+	}
+	if want := []string{"all=-N", "-l"}; !reflect.DeepEqual(config.GCFlags, want) {
+		t.Errorf("Expected gcflags %q, got %q", want, config.GCFlags)
+	}
+	if want := []string{"boringcrypto", "loopvar"}; !reflect.DeepEqual(config.Experiments, want) {
+		t.Errorf("Expected experiments %v, got %v", want, config.Experiments)
+	}
+
+	if config.VCS == nil {
+		t.Fatal("Expected VCS info, got nil")
+	}
+	if config.VCS.System != "git" || !config.VCS.Modified {
+		t.Errorf("Unexpected VCS info: %+v", config.VCS)
+	}
+	if want := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC); !config.VCS.Time.Equal(want) {
+		t.Errorf("Expected VCS time %v, got %v", want, config.VCS.Time)
+	}
+}
+
+func TestNewBuildConfig_Empty(t *testing.T) {
+	config := NewBuildConfig(nil)
+	if config == nil {
+		t.Fatal("Expected non-nil config for empty settings")
+	}
+	if config.VCS != nil || config.Tags != nil || config.ArchFeatures != nil {
+		t.Errorf("Expected zero config, got %+v", config)
+	}
+}
+
+func TestSplitQuotedArgs(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"-s -w", []string{"-s", "-w"}},
+		{`-X 'main.msg=hello world'  -s`, []string{"-X", "main.msg=hello world", "-s"}},
+		{`-extldflags "-static"`, []string{"-extldflags", "-static"}},
+		{`-X "unterminated`, []string{"-X", `"unterminated`}},
+	}
+
+	for _, tt := range tests {
+		if got := splitQuotedArgs(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQuotedArgs(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
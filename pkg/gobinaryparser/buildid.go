@@ -0,0 +1,195 @@
+package gobinaryparser
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 链接器写入文本段开头的构建ID标记，格式为 "\xff Go build ID: \"<id>\"\n \xff"
+var (
+	goBuildIDPrefix = []byte("\xff Go build ID: \"")
+	goBuildIDEnd    = []byte("\"\n \xff")
+)
+
+// buildIDSearchSize 是在文本段开头搜索构建ID时读取的字节数，与 go tool buildid 一致
+const buildIDSearchSize = 32 * 1024
+
+// ReadBuildID 读取Go二进制文件中由链接器写入的构建ID，即 go tool buildid 输出的值。
+// ELF文件从 .note.go.buildid 注释段读取，其他格式从文本段开头的构建ID标记读取，
+// WebAssembly模块从 go:buildid 自定义段读取。
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - *GoBuildID: 构建ID及其拆分出的action ID和content ID
+//   - error: 如果格式无法识别或二进制文件中没有构建ID（例如使用了 -ldflags=-buildid=），则返回错误信息
+//
+// 使用示例:
+//
+//	f, _ := os.Open("/usr/local/bin/kubectl")
+//	defer f.Close()
+//	buildID, err := gobinaryparser.ReadBuildID(f)
+//	if err != nil {
+//		log.Fatalf("读取构建ID失败: %v", err)
+//	}
+//	fmt.Printf("构建ID: %s\n", buildID.ID)
+//	fmt.Printf("内容ID: %s\n", buildID.ContentID)
+func ReadBuildID(r io.ReaderAt) (*GoBuildID, error) {
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+	return exe.buildID()
+}
+
+// NewGoBuildID 将构建ID字符串拆分为action ID和content ID。
+// 可执行文件的构建ID由四部分组成：
+// actionID(二进制)/actionID(main.a)/contentID(main.a)/contentID(二进制)，
+// 其中第一部分是action ID，最后一部分是content ID。
+//
+// 参数:
+//   - id: go tool buildid 输出的构建ID
+//
+// 返回:
+//   - *GoBuildID: 拆分后的构建ID
+//
+// 使用示例:
+//
+//	buildID := gobinaryparser.NewGoBuildID("abc/def/ghi/jkl")
+//	fmt.Println(buildID.ActionID)  // abc
+//	fmt.Println(buildID.ContentID) // jkl
+func NewGoBuildID(id string) *GoBuildID {
+	buildID := &GoBuildID{ID: id}
+	if i := strings.IndexByte(id, '/'); i >= 0 {
+		buildID.ActionID = id[:i]
+		buildID.ContentID = id[strings.LastIndexByte(id, '/')+1:]
+	}
+	return buildID
+}
+
+// buildID 根据可执行文件格式读取构建ID
+func (e *executable) buildID() (*GoBuildID, error) {
+	var id string
+	var err error
+
+	switch e.format {
+	case FormatELF:
+		id, err = e.elfNoteBuildID()
+		if id == "" && err == nil {
+			if sect := e.elf.Section(".text"); sect != nil {
+				id, err = readRawBuildID(e.r, int64(sect.Offset), int64(sect.Size))
+			}
+		}
+	case FormatPE:
+		if sect := e.pe.Section(".text"); sect != nil {
+			id, err = readRawBuildID(e.r, int64(sect.Offset), int64(sect.Size))
+		}
+	case FormatMachO:
+		if sect := e.macho.Section("__text"); sect != nil {
+			id, err = readRawBuildID(e.r, int64(sect.Offset), int64(sect.Size))
+		}
+	case FormatWasm:
+		id, err = wasmBuildID(e.r)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, fmt.Errorf("未找到Go构建ID")
+	}
+	return NewGoBuildID(id), nil
+}
+
+// elfNoteBuildID 从ELF文件的PT_NOTE段中读取Go构建ID注释
+func (e *executable) elfNoteBuildID() (string, error) {
+	const goBuildIDTag = 4
+
+	for _, prog := range e.elf.Progs {
+		if prog.Type != elf.PT_NOTE || prog.Filesz < 16 {
+			continue
+		}
+
+		note := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(note, 0); err != nil {
+			return "", fmt.Errorf("读取ELF注释段失败: %w", err)
+		}
+
+		for len(note) >= 16 {
+			nameSize := e.elf.ByteOrder.Uint32(note)
+			descSize := e.elf.ByteOrder.Uint32(note[4:])
+			tag := e.elf.ByteOrder.Uint32(note[8:])
+			nameEnd := 12 + alignUp(uint64(nameSize), 4)
+			descEnd := nameEnd + alignUp(uint64(descSize), 4)
+			if descEnd > uint64(len(note)) {
+				break
+			}
+			if tag == goBuildIDTag && nameSize == 4 && string(note[12:16]) == "Go\x00\x00" {
+				return string(note[nameEnd : nameEnd+uint64(descSize)]), nil
+			}
+			note = note[descEnd:]
+		}
+	}
+	return "", nil
+}
+
+// readRawBuildID 在文本段开头搜索链接器写入的构建ID标记
+func readRawBuildID(r io.ReaderAt, offset, size int64) (string, error) {
+	if size > buildIDSearchSize {
+		size = buildIDSearchSize
+	}
+	data := make([]byte, size)
+	n, err := r.ReadAt(data, offset)
+	if n < len(data) && err != nil && err != io.EOF {
+		return "", fmt.Errorf("读取文本段失败: %w", err)
+	}
+	data = data[:n]
+
+	i := bytes.Index(data, goBuildIDPrefix)
+	if i < 0 {
+		return "", nil
+	}
+	j := bytes.Index(data[i+len(goBuildIDPrefix):], goBuildIDEnd)
+	if j < 0 {
+		return "", fmt.Errorf("构建ID格式错误")
+	}
+
+	quoted := data[i+len(goBuildIDPrefix)-1 : i+len(goBuildIDPrefix)+j+1]
+	id, err := strconv.Unquote(string(quoted))
+	if err != nil {
+		return "", fmt.Errorf("构建ID格式错误: %w", err)
+	}
+	return id, nil
+}
+
+// wasmBuildID 从wasm模块的 go:buildid 自定义段读取构建ID
+func wasmBuildID(r io.ReaderAt) (string, error) {
+	var id string
+	err := forEachWasmSection(r, func(sectionID byte, section *bufio.Reader) error {
+		if sectionID != wasmCustomSection || id != "" {
+			return nil
+		}
+		name, err := readWasmName(section)
+		if err != nil || name != "go:buildid" {
+			return err
+		}
+		data, err := io.ReadAll(section)
+		if err != nil {
+			return fmt.Errorf("读取wasm构建ID失败: %w", err)
+		}
+		id = string(data)
+		return nil
+	})
+	return id, err
+}
+
+// alignUp 将n向上对齐到align的整数倍
+func alignUp(n, align uint64) uint64 {
+	return (n + align - 1) &^ (align - 1)
+}
@@ -0,0 +1,87 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestReadBuildID(t *testing.T) {
+	path := testBinaryPath(t)
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open test binary: %v", err)
+	}
+	defer f.Close()
+
+	buildID, err := ReadBuildID(f)
+	if err != nil {
+		t.Fatalf("ReadBuildID failed: %v", err)
+	}
+	if buildID.ID == "" || buildID.ActionID == "" || buildID.ContentID == "" {
+		t.Fatalf("Expected complete build ID, got %+v", buildID)
+	}
+
+	// Cross-check with the Go toolchain when it is available
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not available, skipping comparison with go tool buildid")
+	}
+	out, err := exec.Command(goTool, "tool", "buildid", path).Output()
+	if err != nil {
+		t.Skipf("go tool buildid failed: %v", err)
+	}
+	if want := strings.TrimSpace(string(out)); buildID.ID != want {
+		t.Errorf("Expected build ID %q, got %q", want, buildID.ID)
+	}
+}
+
+func TestReadBuildID_Invalid(t *testing.T) {
+	_, err := ReadBuildID(bytes.NewReader([]byte("This is not a Go binary")))
+	if err == nil {
+		t.Error("Expected error for invalid data, got nil")
+	}
+}
+
+func TestNewGoBuildID(t *testing.T) {
+	buildID := NewGoBuildID("aaa/bbb/ccc/ddd")
+	if buildID.ActionID != "aaa" || buildID.ContentID != "ddd" {
+		t.Errorf("Unexpected split of build ID: %+v", buildID)
+	}
+
+	buildID = NewGoBuildID("single")
+	if buildID.ActionID != "" || buildID.ContentID != "" {
+		t.Errorf("Expected no action or content ID for malformed build ID, got %+v", buildID)
+	}
+}
+
+func TestReadRawBuildID(t *testing.T) {
+	data := append([]byte("\x00\x00"), []byte("\xff Go build ID: \"act/main/cont\"\n \xff")...)
+	data = append(data, make([]byte, 64)...)
+
+	id, err := readRawBuildID(bytes.NewReader(data), 0, int64(len(data)))
+	if err != nil {
+		t.Fatalf("readRawBuildID failed: %v", err)
+	}
+	if id != "act/main/cont" {
+		t.Errorf("Expected build ID act/main/cont, got %q", id)
+	}
+
+	id, err = readRawBuildID(bytes.NewReader(make([]byte, 128)), 0, 128)
+	if err != nil || id != "" {
+		t.Errorf("Expected empty build ID without error, got %q, %v", id, err)
+	}
+}
+
+func TestParseBinary_BuildID(t *testing.T) {
+	info, err := ParseBinary(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("ParseBinary failed: %v", err)
+	}
+	if info.BuildID == nil || info.BuildID.ID == "" {
+		t.Errorf("Expected build ID to be populated, got %+v", info.BuildID)
+	}
+}
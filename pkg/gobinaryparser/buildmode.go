@@ -0,0 +1,89 @@
+package gobinaryparser
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"strings"
+)
+
+// pluginTabSymbols 是Go插件中记录导出符号表的符号，旧版本链接器使用 go.plugin.tabs
+var pluginTabSymbols = []string{"go:plugin.tabs", "go.plugin.tabs"}
+
+// resolveBuildMode 返回二进制文件的构建模式。
+// Go 1.18及以上版本在构建设置中记录了 -buildmode，缺失时根据文件结构推断。
+func resolveBuildMode(settings map[string]string, exe *executable) string {
+	if mode := settings["-buildmode"]; mode != "" {
+		return mode
+	}
+	if exe == nil {
+		return ""
+	}
+	return exe.buildMode()
+}
+
+// buildMode 根据文件类型、动态链接器和插件符号推断构建模式。
+// 目标文件（例如静态库中的 go.o）无法单独判断构建模式，返回空字符串。
+func (e *executable) buildMode() string {
+	switch e.format {
+	case FormatELF:
+		switch e.elf.Type {
+		case elf.ET_EXEC:
+			return BuildModeExe
+		case elf.ET_DYN:
+			if e.isGoPlugin() {
+				return BuildModePlugin
+			}
+			format := elfFormatInfo(e.elf)
+			if format.PIE {
+				return BuildModePIE
+			}
+			return BuildModeCShared
+		}
+	case FormatMachO:
+		switch e.macho.Type {
+		case macho.TypeExec:
+			if e.macho.Flags&macho.FlagPIE != 0 {
+				return BuildModePIE
+			}
+			return BuildModeExe
+		case macho.TypeDylib, macho.TypeBundle:
+			if e.isGoPlugin() {
+				return BuildModePlugin
+			}
+			return BuildModeCShared
+		}
+	case FormatPE:
+		format := peFormatInfo(e.pe)
+		switch {
+		case format.Shared:
+			return BuildModeCShared
+		case format.PIE:
+			return BuildModePIE
+		}
+		return BuildModeExe
+	case FormatWasm:
+		return BuildModeExe
+	}
+	return ""
+}
+
+// isGoPlugin 判断共享库是否为Go插件
+func (e *executable) isGoPlugin() bool {
+	for _, name := range pluginTabSymbols {
+		if _, err := e.lookupSymbol(name); err == nil {
+			return true
+		}
+	}
+	// 插件使用 -dynlink 编译，剥离了符号表后仍然在动态符号表中导出各个包的哈希符号，
+	// 而c-shared共享库只导出 //export 标记的函数
+	if e.format == FormatELF {
+		if syms, err := e.elf.DynamicSymbols(); err == nil {
+			for _, sym := range syms {
+				if strings.HasPrefix(sym.Name, "go:link.pkghash") || strings.HasPrefix(sym.Name, "go.link.pkghash") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
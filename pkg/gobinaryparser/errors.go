@@ -0,0 +1,109 @@
+package gobinaryparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 解析过程中可能返回的哨兵错误，可以用 errors.Is 判断失败的原因
+var (
+	// ErrNotGoBinary 表示输入是可识别的可执行文件或静态库，但不是由Go编译的
+	ErrNotGoBinary = errors.New("不是Go二进制文件")
+	// ErrNoBuildInfo 表示输入是Go二进制文件，但构建信息缺失或损坏，且无法以降级模式恢复
+	ErrNoBuildInfo = errors.New("Go二进制文件中没有可读取的构建信息")
+	// ErrUnsupportedFormat 表示输入不是支持的可执行文件格式（ELF、PE、Mach-O、wasm或ar静态库）
+	ErrUnsupportedFormat = errors.New("不支持的文件格式")
+	// ErrTooLarge 表示输入数据超过了允许的最大大小
+	ErrTooLarge = errors.New("输入数据超过大小限制")
+	// ErrNotFound 表示本地文件不存在或远程服务器返回了404/410
+	ErrNotFound = errors.New("二进制文件不存在")
+	// ErrRemoteChanged 表示远程文件在通过范围请求读取的过程中被替换（ETag或大小发生了变化）
+	ErrRemoteChanged = errors.New("远程文件在读取过程中发生了变化")
+)
+
+// sentinelErrors 按判断优先级排列的哨兵错误
+var sentinelErrors = []error{ErrNotFound, ErrRemoteChanged, ErrTooLarge, ErrUnsupportedFormat, ErrNotGoBinary, ErrNoBuildInfo}
+
+// ParseError 是解析函数返回的错误类型，记录了解析的来源以及底层原因。
+// 底层原因通常包装了一个哨兵错误，可以直接对 *ParseError 使用 errors.Is。
+//
+// 使用示例:
+//
+//	info, err := gobinaryparser.ParseBinaryFromFile("/usr/local/bin/kubectl")
+//	var perr *gobinaryparser.ParseError
+//	switch {
+//	case errors.Is(err, gobinaryparser.ErrNotGoBinary):
+//		fmt.Println("不是Go二进制文件")
+//	case errors.As(err, &perr):
+//		fmt.Printf("解析 %s (%s) 失败: %v\n", perr.Source, perr.SourceType, perr.Err)
+//	}
+type ParseError struct {
+	Source     string // 文件路径或URL，从字节或读取器解析时为空
+	SourceType string // 源类型（"file"、"url"、"bytes"、"reader"、"stream"）
+	Err        error  // 底层原因
+}
+
+// Error 实现error接口
+func (e *ParseError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("解析二进制文件失败 (%s): %v", e.SourceType, e.Err)
+	}
+	return fmt.Sprintf("解析二进制文件 %s 失败: %v", e.Source, e.Err)
+}
+
+// Unwrap 返回底层原因，使 errors.Is 和 errors.As 可以检查哨兵错误
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// RetryError 表示HTTP请求在尝试了Attempts次后仍然失败，Err是最后一次尝试的错误。
+// 网络错误、429和5xx状态码以及中断的范围请求响应都会被包装为 *RetryError。
+//
+// 使用示例:
+//
+//	_, err := parser.ParseBinaryFromRemoteFile(url)
+//	var rerr *gobinaryparser.RetryError
+//	if errors.As(err, &rerr) {
+//		fmt.Printf("请求失败，共尝试%d次: %v\n", rerr.Attempts, rerr.Err)
+//	}
+type RetryError struct {
+	Attempts int   // 尝试次数（包括第一次请求）
+	Err      error // 最后一次尝试的错误
+}
+
+// Error 实现error接口
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (共尝试%d次)", e.Err, e.Attempts)
+}
+
+// Unwrap 返回最后一次尝试的错误
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError 将err包装为 *ParseError，err已经是 *ParseError 时原样返回
+func newParseError(source string, sourceType string, err error) error {
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		return err
+	}
+	return &ParseError{Source: source, SourceType: sourceType, Err: err}
+}
+
+// classifyReadError 为读取构建信息的错误附加哨兵错误。
+// 标准库返回的错误无法区分失败原因，因此根据降级恢复失败的原因判断：
+// 文件格式无法识别、没有pclntab（不是Go二进制文件）或pclntab损坏（没有构建信息）。
+// 读取本身失败（例如网络错误）时原样返回。
+func classifyReadError(err error, recoverErr error) error {
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel) {
+			return err
+		}
+	}
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(recoverErr, sentinel) {
+			return fmt.Errorf("%w: %w", sentinel, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"context"
+	"debug/elf"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// corruptedTestBinary 返回破坏了构建信息魔数的测试二进制文件，仅支持ELF格式
+func corruptedTestBinary(t *testing.T) ([]byte, *elf.Section) {
+	t.Helper()
+
+	data := readTestBinary(t)
+	f, err := elf.NewFile(bytes.NewReader(data))
+	if err != nil {
+		t.Skip("Test binary is not an ELF file")
+	}
+	sect := f.Section(".gopclntab")
+	if sect == nil {
+		t.Skip("Test binary has no .gopclntab section")
+	}
+	return bytes.ReplaceAll(data, buildInfoMagic, []byte("\xff Go xxxxxxxx:")), sect
+}
+
+func TestParseError_Sentinels(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing")
+		_, err := ParseBinaryFromFile(path)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("error = %v, want ErrNotFound", err)
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Source != path || perr.SourceType != "file" {
+			t.Errorf("error = %#v, want *ParseError for %s", err, path)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := ParseBinaryFromBytes([]byte("This is not a Go binary"))
+		if !errors.Is(err, ErrUnsupportedFormat) {
+			t.Errorf("error = %v, want ErrUnsupportedFormat", err)
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.SourceType != "bytes" {
+			t.Errorf("error = %#v, want *ParseError with source type bytes", err)
+		}
+	})
+
+	t.Run("not a Go binary", func(t *testing.T) {
+		data, _ := corruptedTestBinary(t)
+		// 在节名字符串表中重命名pclntab段，模拟不包含Go运行时的ELF文件。
+		// 使用-race构建时数据段中也有相同的字符串，因此只替换节名字符串表
+		f, _ := elf.NewFile(bytes.NewReader(data))
+		shstrtab := f.Section(".shstrtab")
+		if shstrtab == nil {
+			t.Skip("Test binary has no .shstrtab section")
+		}
+		names := data[shstrtab.Offset : shstrtab.Offset+shstrtab.Size]
+		copy(names, bytes.Replace(names, []byte(".gopclntab\x00"), []byte(".xopclntab\x00"), 1))
+
+		_, err := ParseBinaryFromBytes(data)
+		if !errors.Is(err, ErrNotGoBinary) {
+			t.Errorf("error = %v, want ErrNotGoBinary", err)
+		}
+	})
+
+	t.Run("no build info", func(t *testing.T) {
+		data, sect := corruptedTestBinary(t)
+		// 破坏pclntab头部的魔数，降级模式也无法恢复
+		copy(data[sect.Offset:], []byte{0, 0, 0, 0})
+
+		_, err := ParseBinaryFromBytes(data)
+		if !errors.Is(err, ErrNoBuildInfo) {
+			t.Errorf("error = %v, want ErrNoBuildInfo", err)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := ParseBinaryFromStream(context.Background(), bytes.NewReader(make([]byte, 2048)), &StreamOptions{MaxSize: 1024})
+		if !errors.Is(err, ErrTooLarge) {
+			t.Errorf("error = %v, want ErrTooLarge", err)
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.SourceType != "stream" {
+			t.Errorf("error = %#v, want *ParseError with source type stream", err)
+		}
+	})
+}
+
+func TestParseError_HTTPNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := ParseBinaryFromURL(server.URL); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ParseBinaryFromURL() error = %v, want ErrNotFound", err)
+	}
+
+	_, err := ParseBinaryFromRemoteFile(server.URL)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("ParseBinaryFromRemoteFile() error = %v, want ErrNotFound", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Source != server.URL || perr.SourceType != "url" {
+		t.Errorf("error = %#v, want *ParseError for %s", err, server.URL)
+	}
+}
+
+func TestClassifyReadError(t *testing.T) {
+	cause := errors.New("not a Go executable")
+
+	tests := []struct {
+		name       string
+		err        error
+		recoverErr error
+		want       error
+	}{
+		{"unsupported", cause, ErrUnsupportedFormat, ErrUnsupportedFormat},
+		{"no pclntab", cause, errors.Join(ErrNotGoBinary, io.EOF), ErrNotGoBinary},
+		{"already classified", ErrNotFound, ErrNotGoBinary, ErrNotFound},
+		{"read error", io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyReadError(tt.err, tt.recoverErr)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classifyReadError() = %v, lost original error %v", err, tt.err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("classifyReadError() = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil {
+				for _, sentinel := range sentinelErrors {
+					if errors.Is(err, sentinel) {
+						t.Errorf("classifyReadError() = %v, unexpectedly wraps %v", err, sentinel)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,569 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// executable 对ELF、PE和Mach-O可执行文件提供统一的访问方式，
+// 用于读取buildinfo之外的信息，例如pclntab和符号表。
+type executable struct {
+	format string
+	r      io.ReaderAt
+	elf    *elf.File
+	pe     *pe.File
+	macho  *macho.File
+
+	// relocs 是位置无关的ELF文件中相对重定位的目标地址到值的映射，第一次使用时加载
+	relocs map[uint64]uint64
+}
+
+// openExecutable 根据文件头的魔数识别可执行文件格式并打开它
+//
+// 参数:
+//   - r: 可执行文件内容的读取器
+//
+// 返回:
+//   - *executable: 打开的可执行文件
+//   - error: 如果格式无法识别或文件头损坏，则返回错误信息
+func openExecutable(r io.ReaderAt) (*executable, error) {
+	ident := make([]byte, 16)
+	if n, err := r.ReadAt(ident, 0); n < len(ident) && err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: 文件过短", ErrUnsupportedFormat)
+		}
+		return nil, fmt.Errorf("读取文件头失败: %w", err)
+	}
+
+	exe := &executable{r: r}
+	var err error
+	switch {
+	case bytes.HasPrefix(ident, []byte("\x7FELF")):
+		exe.format = FormatELF
+		exe.elf, err = elf.NewFile(r)
+	case bytes.HasPrefix(ident, []byte("MZ")):
+		exe.format = FormatPE
+		exe.pe, err = pe.NewFile(r)
+	case isMachOMagic(ident):
+		exe.format = FormatMachO
+		exe.macho, err = macho.NewFile(r)
+	case bytes.HasPrefix(ident, []byte("\x00asm")):
+		exe.format = FormatWasm
+	default:
+		return nil, fmt.Errorf("%w: 无法识别的可执行文件格式", ErrUnsupportedFormat)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: 解析%s文件失败: %w", ErrUnsupportedFormat, exe.format, err)
+	}
+	return exe, nil
+}
+
+// isMachOMagic 判断文件头是否为单架构Mach-O文件的魔数
+func isMachOMagic(ident []byte) bool {
+	for _, magic := range [][]byte{
+		{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf},
+		{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	} {
+		if bytes.HasPrefix(ident, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// pclntab 返回文本段起始地址和pclntab的原始数据
+func (e *executable) pclntab() (textStart uint64, data []byte, err error) {
+	switch e.format {
+	case FormatELF:
+		if sect := e.elf.Section(".text"); sect != nil {
+			textStart = sect.Addr
+		}
+		sect := e.elf.Section(".gopclntab")
+		if sect == nil {
+			// PIE二进制文件中pclntab位于只读重定位数据段
+			sect = e.elf.Section(".data.rel.ro.gopclntab")
+		}
+		if sect == nil {
+			return 0, nil, fmt.Errorf("%w: 未找到pclntab段", ErrNotGoBinary)
+		}
+		data, err = sect.Data()
+		return textStart, data, err
+
+	case FormatPE:
+		imageBase := peImageBase(e.pe)
+		if sect := e.pe.Section(".text"); sect != nil {
+			textStart = imageBase + uint64(sect.VirtualAddress)
+		}
+		data, err = peSymbolRange(e.pe, "runtime.pclntab", "runtime.epclntab")
+		if err != nil {
+			return 0, nil, fmt.Errorf("%w: %w", ErrNotGoBinary, err)
+		}
+		return textStart, data, nil
+
+	case FormatMachO:
+		if sect := e.macho.Section("__text"); sect != nil {
+			textStart = sect.Addr
+		}
+		sect := e.macho.Section("__gopclntab")
+		if sect == nil {
+			return 0, nil, fmt.Errorf("%w: 未找到pclntab段", ErrNotGoBinary)
+		}
+		data, err = sect.Data()
+		return textStart, data, err
+	}
+	// Go 1.18及以上版本编译的wasm模块都包含构建信息，不需要从pclntab恢复
+	return 0, nil, fmt.Errorf("%w: %s格式不支持读取pclntab", ErrNotGoBinary, e.format)
+}
+
+// symbolTable 从pclntab构建Go符号表
+func (e *executable) symbolTable() (*gosym.Table, error) {
+	textStart, data, err := e.pclntab()
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, textStart))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 解析pclntab失败: %w", ErrNoBuildInfo, err)
+	}
+	return table, nil
+}
+
+// byteOrder 返回可执行文件的字节序
+func (e *executable) byteOrder() binary.ByteOrder {
+	switch e.format {
+	case FormatELF:
+		return e.elf.ByteOrder
+	case FormatMachO:
+		return e.macho.ByteOrder
+	}
+	return binary.LittleEndian
+}
+
+// ptrSize 返回目标平台的指针大小（字节）
+func (e *executable) ptrSize() int {
+	switch e.format {
+	case FormatELF:
+		if e.elf.Class == elf.ELFCLASS64 {
+			return 8
+		}
+	case FormatPE:
+		if _, ok := e.pe.OptionalHeader.(*pe.OptionalHeader64); ok {
+			return 8
+		}
+	case FormatMachO:
+		if e.macho.Magic == macho.Magic64 {
+			return 8
+		}
+	}
+	return 4
+}
+
+// fileOffset 将虚拟地址转换为文件偏移，并返回从该地址起在文件中连续可读的字节数
+func (e *executable) fileOffset(addr uint64) (offset int64, avail uint64, ok bool) {
+	switch e.format {
+	case FormatELF:
+		for _, prog := range e.elf.Progs {
+			if prog.Type == elf.PT_LOAD && addr >= prog.Vaddr && addr < prog.Vaddr+prog.Filesz {
+				return int64(prog.Off + addr - prog.Vaddr), prog.Vaddr + prog.Filesz - addr, true
+			}
+		}
+	case FormatPE:
+		imageBase := peImageBase(e.pe)
+		for _, sect := range e.pe.Sections {
+			start := imageBase + uint64(sect.VirtualAddress)
+			size := uint64(sect.VirtualSize)
+			if uint64(sect.Size) < size {
+				size = uint64(sect.Size)
+			}
+			if addr >= start && addr < start+size {
+				return int64(uint64(sect.Offset) + addr - start), start + size - addr, true
+			}
+		}
+	case FormatMachO:
+		for _, load := range e.macho.Loads {
+			seg, isSeg := load.(*macho.Segment)
+			if isSeg && addr >= seg.Addr && addr < seg.Addr+seg.Filesz {
+				return int64(seg.Offset + addr - seg.Addr), seg.Addr + seg.Filesz - addr, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// readVirtual 读取虚拟地址addr处的size个字节
+func (e *executable) readVirtual(addr uint64, size int) ([]byte, error) {
+	offset, avail, ok := e.fileOffset(addr)
+	if !ok || avail < uint64(size) {
+		return nil, fmt.Errorf("地址 0x%x 不在文件映射的段中", addr)
+	}
+	data := make([]byte, size)
+	if _, err := e.r.ReadAt(data, offset); err != nil {
+		return nil, fmt.Errorf("读取地址 0x%x 失败: %w", addr, err)
+	}
+	return data, nil
+}
+
+// readPointer 从数据中按目标平台的指针大小和字节序解码一个指针
+func (e *executable) readPointer(data []byte) uint64 {
+	if e.ptrSize() == 8 {
+		return e.byteOrder().Uint64(data)
+	}
+	return uint64(e.byteOrder().Uint32(data))
+}
+
+// pointerAt 返回虚拟地址addr处的指针值，data是从该地址读取的数据。
+// 位置无关的ELF文件（例如 -buildmode=pie）中，数据段中的指针在文件中可能为0，
+// 实际值由动态链接器根据 R_*_RELATIVE 重定位写入，此时从重定位表中读取。
+func (e *executable) pointerAt(addr uint64, data []byte) uint64 {
+	if ptr := e.readPointer(data); ptr != 0 || e.format != FormatELF || e.elf.Type != elf.ET_DYN {
+		return ptr
+	}
+	if e.relocs == nil {
+		e.relocs = e.elfRelativeRelocs()
+	}
+	return e.relocs[addr]
+}
+
+// elfRelativeRelocs 读取ELF文件中带显式加数的相对重定位（SHT_RELA），返回目标地址到加数的映射。
+// 不带加数的重定位（SHT_REL）把值直接保存在文件中，不需要处理。
+func (e *executable) elfRelativeRelocs() map[uint64]uint64 {
+	relocs := make(map[uint64]uint64)
+	var relative uint32
+	switch e.elf.Machine {
+	case elf.EM_X86_64:
+		relative = uint32(elf.R_X86_64_RELATIVE)
+	case elf.EM_AARCH64:
+		relative = uint32(elf.R_AARCH64_RELATIVE)
+	case elf.EM_PPC64:
+		relative = uint32(elf.R_PPC64_RELATIVE)
+	case elf.EM_S390:
+		relative = uint32(elf.R_390_RELATIVE)
+	case elf.EM_RISCV:
+		relative = uint32(elf.R_RISCV_RELATIVE)
+	case elf.EM_LOONGARCH:
+		relative = uint32(elf.R_LARCH_RELATIVE)
+	case elf.EM_386:
+		relative = uint32(elf.R_386_RELATIVE)
+	case elf.EM_ARM:
+		relative = uint32(elf.R_ARM_RELATIVE)
+	default:
+		return relocs
+	}
+
+	order := e.byteOrder()
+	is64 := e.elf.Class == elf.ELFCLASS64
+	for _, s := range e.elf.Sections {
+		if s.Type != elf.SHT_RELA {
+			continue
+		}
+		data, err := s.Data()
+		if err != nil {
+			continue
+		}
+		if is64 {
+			for i := 0; i+24 <= len(data); i += 24 {
+				if uint32(order.Uint64(data[i+8:])) == relative {
+					relocs[order.Uint64(data[i:])] = order.Uint64(data[i+16:])
+				}
+			}
+		} else {
+			for i := 0; i+12 <= len(data); i += 12 {
+				if order.Uint32(data[i+4:])&0xff == relative {
+					relocs[uint64(order.Uint32(data[i:]))] = uint64(order.Uint32(data[i+8:]))
+				}
+			}
+		}
+	}
+	return relocs
+}
+
+// readGoString 读取虚拟地址addr处的Go字符串头（数据指针和长度）所指向的字符串
+func (e *executable) readGoString(addr uint64) (string, error) {
+	s, _, err := e.readGoStringAt(addr)
+	return s, err
+}
+
+// readGoStringAt 与 readGoString 相同，同时返回字符串数据在文件中的偏移，空字符串的偏移为-1
+func (e *executable) readGoStringAt(addr uint64) (string, int64, error) {
+	ptrSize := e.ptrSize()
+	header, err := e.readVirtual(addr, 2*ptrSize)
+	if err != nil {
+		return "", -1, err
+	}
+	dataAddr := e.pointerAt(addr, header)
+	length := e.readPointer(header[ptrSize:])
+	if length == 0 {
+		return "", -1, nil
+	}
+	if length > 1<<20 {
+		return "", -1, fmt.Errorf("字符串长度 %d 无效", length)
+	}
+	offset, _, _ := e.fileOffset(dataAddr)
+	data, err := e.readVirtual(dataAddr, int(length))
+	if err != nil {
+		return "", -1, err
+	}
+	return string(data), offset, nil
+}
+
+// lookupSymbol 在符号表中查找指定名称的符号
+func (e *executable) lookupSymbol(name string) (*exeSymbol, error) {
+	syms, err := e.symbols()
+	if err != nil {
+		return nil, err
+	}
+	for i := range syms {
+		if syms[i].Name == name {
+			return &syms[i], nil
+		}
+	}
+	return nil, fmt.Errorf("未找到符号 %s", name)
+}
+
+// 符号所在段的类型
+const (
+	symText   = 'T' // 可执行代码
+	symRodata = 'R' // 只读数据
+	symData   = 'D' // 可写的已初始化数据
+	symBSS    = 'B' // 未初始化数据，不占用文件空间
+)
+
+// exeSymbol 表示可执行文件符号表中的一个符号
+type exeSymbol struct {
+	Name string
+	Addr uint64
+	Size uint64
+	Kind byte
+}
+
+// symbols 返回可执行文件符号表中的所有符号，按地址排序。
+// 对于不记录符号大小的格式（Mach-O和PE），大小按同一段中下一个符号的地址推算。
+func (e *executable) symbols() ([]exeSymbol, error) {
+	var syms []exeSymbol
+	needSizes := false
+
+	switch e.format {
+	case FormatELF:
+		elfSyms, err := e.elf.Symbols()
+		if err != nil {
+			return nil, fmt.Errorf("读取符号表失败: %w", err)
+		}
+		for _, s := range elfSyms {
+			typ := elf.ST_TYPE(s.Info)
+			if typ == elf.STT_SECTION || typ == elf.STT_FILE || s.Name == "" {
+				continue
+			}
+			if s.Section == elf.SHN_UNDEF || s.Section >= elf.SHN_LORESERVE || int(s.Section) >= len(e.elf.Sections) {
+				continue
+			}
+			syms = append(syms, exeSymbol{
+				Name: s.Name,
+				Addr: s.Value,
+				Size: s.Size,
+				Kind: elfSectionKind(e.elf.Sections[s.Section]),
+			})
+		}
+
+	case FormatMachO:
+		if e.macho.Symtab == nil {
+			return nil, fmt.Errorf("读取符号表失败: 没有符号表")
+		}
+		needSizes = true
+		for _, s := range e.macho.Symtab.Syms {
+			// 只保留定义在某个段中的符号（N_SECT），跳过调试符号
+			if s.Type&0xe0 != 0 || s.Type&0x0e != 0x0e || s.Sect == 0 || int(s.Sect) > len(e.macho.Sections) {
+				continue
+			}
+			syms = append(syms, exeSymbol{
+				Name: strings.TrimPrefix(s.Name, "_"),
+				Addr: s.Value,
+				Kind: machoSectionKind(e.macho.Sections[s.Sect-1]),
+			})
+		}
+
+	case FormatPE:
+		if len(e.pe.Symbols) == 0 {
+			return nil, fmt.Errorf("读取符号表失败: 没有符号表")
+		}
+		needSizes = true
+		imageBase := peImageBase(e.pe)
+		for _, s := range e.pe.Symbols {
+			if s.SectionNumber <= 0 || int(s.SectionNumber) > len(e.pe.Sections) {
+				continue
+			}
+			sect := e.pe.Sections[s.SectionNumber-1]
+			syms = append(syms, exeSymbol{
+				Name: s.Name,
+				Addr: imageBase + uint64(sect.VirtualAddress) + uint64(s.Value),
+				Kind: peSectionKind(sect),
+			})
+		}
+
+	default:
+		return nil, fmt.Errorf("%s格式不支持读取符号表", e.format)
+	}
+
+	sort.SliceStable(syms, func(i, j int) bool { return syms[i].Addr < syms[j].Addr })
+
+	if needSizes {
+		ends := e.sectionEnds()
+		for i := range syms {
+			end := sectionEndFor(ends, syms[i].Addr)
+			if i+1 < len(syms) && syms[i+1].Addr < end {
+				end = syms[i+1].Addr
+			}
+			if end > syms[i].Addr {
+				syms[i].Size = end - syms[i].Addr
+			}
+		}
+	}
+
+	return syms, nil
+}
+
+// sectionRange 表示一个段的虚拟地址范围和类型
+type sectionRange struct {
+	Start, End uint64
+	Kind       byte
+}
+
+// sections 返回所有加载到内存中的段的地址范围和类型
+func (e *executable) sections() []sectionRange {
+	var ranges []sectionRange
+	switch e.format {
+	case FormatELF:
+		for _, s := range e.elf.Sections {
+			if s.Flags&elf.SHF_ALLOC != 0 && s.Size > 0 {
+				ranges = append(ranges, sectionRange{s.Addr, s.Addr + s.Size, elfSectionKind(s)})
+			}
+		}
+	case FormatMachO:
+		for _, s := range e.macho.Sections {
+			if s.Size > 0 {
+				ranges = append(ranges, sectionRange{s.Addr, s.Addr + s.Size, machoSectionKind(s)})
+			}
+		}
+	case FormatPE:
+		imageBase := peImageBase(e.pe)
+		for _, s := range e.pe.Sections {
+			start := imageBase + uint64(s.VirtualAddress)
+			if size := uint64(s.VirtualSize); size > 0 {
+				ranges = append(ranges, sectionRange{start, start + size, peSectionKind(s)})
+			}
+		}
+	}
+	return ranges
+}
+
+// sectionEnds 返回按起始地址排序的段范围，用于推算符号大小
+func (e *executable) sectionEnds() []sectionRange {
+	ranges := e.sections()
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	return ranges
+}
+
+// sectionEndFor 返回包含给定地址的段的结束地址，不在任何段中时返回该地址本身
+func sectionEndFor(ranges []sectionRange, addr uint64) uint64 {
+	for _, r := range ranges {
+		if addr >= r.Start && addr < r.End {
+			return r.End
+		}
+	}
+	return addr
+}
+
+// elfSectionKind 根据ELF段标志判断段类型
+func elfSectionKind(s *elf.Section) byte {
+	switch {
+	case s.Flags&elf.SHF_EXECINSTR != 0:
+		return symText
+	case s.Type == elf.SHT_NOBITS:
+		return symBSS
+	case s.Flags&elf.SHF_WRITE != 0:
+		return symData
+	}
+	return symRodata
+}
+
+// machoSectionKind 根据Mach-O段名判断段类型
+func machoSectionKind(s *macho.Section) byte {
+	switch {
+	case s.Name == "__text":
+		return symText
+	case s.Name == "__bss" || s.Name == "__noptrbss" || s.Name == "__common":
+		return symBSS
+	case s.Seg == "__DATA":
+		return symData
+	}
+	return symRodata
+}
+
+// peSectionKind 根据PE段属性判断段类型
+func peSectionKind(s *pe.Section) byte {
+	const (
+		cntCode          = 0x00000020
+		cntUninitialized = 0x00000080
+		memWrite         = 0x80000000
+	)
+	switch {
+	case s.Characteristics&cntCode != 0:
+		return symText
+	case s.Characteristics&cntUninitialized != 0:
+		return symBSS
+	case s.Characteristics&memWrite != 0:
+		return symData
+	}
+	return symRodata
+}
+
+// peImageBase 返回PE文件的映像基址
+func peImageBase(f *pe.File) uint64 {
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		return uint64(oh.ImageBase)
+	case *pe.OptionalHeader64:
+		return oh.ImageBase
+	}
+	return 0
+}
+
+// peSymbolRange 返回PE文件中两个符号之间的数据，
+// 用于定位没有独立段的Go运行时表（例如 runtime.pclntab 到 runtime.epclntab）。
+func peSymbolRange(f *pe.File, start, end string) ([]byte, error) {
+	var startSym, endSym *pe.Symbol
+	for _, s := range f.Symbols {
+		switch s.Name {
+		case start:
+			startSym = s
+		case end:
+			endSym = s
+		}
+	}
+	if startSym == nil || endSym == nil {
+		return nil, fmt.Errorf("未找到符号 %s 或 %s", start, end)
+	}
+	if startSym.SectionNumber <= 0 || startSym.SectionNumber != endSym.SectionNumber {
+		return nil, fmt.Errorf("符号 %s 和 %s 不在同一个段中", start, end)
+	}
+	if int(startSym.SectionNumber) > len(f.Sections) {
+		return nil, fmt.Errorf("符号 %s 的段号无效", start)
+	}
+
+	data, err := f.Sections[startSym.SectionNumber-1].Data()
+	if err != nil {
+		return nil, err
+	}
+	if startSym.Value > endSym.Value || uint64(endSym.Value) > uint64(len(data)) {
+		return nil, fmt.Errorf("符号 %s 的范围无效", start)
+	}
+	return data[startSym.Value:endSym.Value], nil
+}
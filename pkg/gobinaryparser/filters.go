@@ -45,6 +45,8 @@ func (info *BinaryInfo) FilterDependencies(filter func(DependencyInfo) bool) []D
 }
 
 // FilterStdLib 过滤依赖列表中的标准库依赖。
+// 注意构建信息只记录模块依赖，标准库包通常不会出现在 Dependencies 中，
+// 如需获取实际链接的标准库包，请使用 ListPackages。
 //
 // 参数:
 //   - include: 为true表示只包含标准库依赖，为false表示排除标准库依赖
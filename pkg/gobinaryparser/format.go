@@ -0,0 +1,422 @@
+package gobinaryparser
+
+import (
+	"bufio"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DetectFormat 识别可执行文件的容器格式并返回格式信息。
+// 该函数不要求文件包含Go构建信息，可以用于任何ELF、PE、Mach-O或WebAssembly文件。
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - *FormatInfo: 可执行文件格式信息
+//   - error: 如果格式无法识别或文件头损坏，则返回错误信息
+//
+// 使用示例:
+//
+//	f, _ := os.Open("/usr/local/bin/kubectl")
+//	defer f.Close()
+//	format, err := gobinaryparser.DetectFormat(f)
+//	if err != nil {
+//		log.Fatalf("识别格式失败: %v", err)
+//	}
+//	fmt.Printf("%s/%s, PIE: %t, 静态链接: %t\n", format.Type, format.Arch, format.PIE, format.Static)
+func DetectFormat(r io.ReaderAt) (*FormatInfo, error) {
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+	return exe.formatInfo()
+}
+
+// formatInfo 根据可执行文件的格式收集格式信息
+func (e *executable) formatInfo() (*FormatInfo, error) {
+	switch e.format {
+	case FormatELF:
+		return elfFormatInfo(e.elf), nil
+	case FormatPE:
+		return peFormatInfo(e.pe), nil
+	case FormatMachO:
+		return machoFormatInfo(e.macho), nil
+	case FormatWasm:
+		return wasmFormatInfo(e.r)
+	}
+	return nil, fmt.Errorf("%s格式不支持读取格式信息", e.format)
+}
+
+// elfFormatInfo 收集ELF文件的格式信息
+func elfFormatInfo(f *elf.File) *FormatInfo {
+	info := &FormatInfo{
+		Type:            FormatELF,
+		Arch:            elfArch(f),
+		Bits:            32,
+		Endian:          endianName(f.ByteOrder),
+		SymbolsStripped: f.Section(".symtab") == nil,
+		DWARFStripped:   f.Section(".debug_info") == nil && f.Section(".zdebug_info") == nil,
+	}
+	if f.Class == elf.ELFCLASS64 {
+		info.Bits = 64
+	}
+
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_INTERP {
+			data := make([]byte, prog.Filesz)
+			if _, err := prog.ReadAt(data, 0); err == nil {
+				info.Interpreter = strings.TrimRight(string(data), "\x00")
+			}
+		}
+	}
+
+	if libs, err := f.ImportedLibraries(); err == nil {
+		info.Libraries = libs
+	}
+
+	if f.Type == elf.ET_DYN {
+		info.PIE = info.Interpreter != ""
+		if flags, err := f.DynValue(elf.DT_FLAGS_1); err == nil {
+			for _, flag := range flags {
+				if elf.DynFlag1(flag)&elf.DF_1_PIE != 0 {
+					info.PIE = true
+				}
+			}
+		}
+	}
+	// 目标文件（例如静态库中的 go.o）尚未链接，不区分静态或动态链接
+	info.Static = f.Type != elf.ET_REL && info.Interpreter == "" && len(info.Libraries) == 0
+	info.Shared = f.Type == elf.ET_DYN && !info.PIE
+
+	return info
+}
+
+// elfArch 将ELF机器类型转换为GOARCH名称
+func elfArch(f *elf.File) string {
+	switch f.Machine {
+	case elf.EM_386:
+		return "386"
+	case elf.EM_X86_64:
+		return "amd64"
+	case elf.EM_ARM:
+		return "arm"
+	case elf.EM_AARCH64:
+		return "arm64"
+	case elf.EM_LOONGARCH:
+		return "loong64"
+	case elf.EM_MIPS:
+		if f.Class == elf.ELFCLASS64 {
+			if f.ByteOrder == binary.LittleEndian {
+				return "mips64le"
+			}
+			return "mips64"
+		}
+		if f.ByteOrder == binary.LittleEndian {
+			return "mipsle"
+		}
+		return "mips"
+	case elf.EM_PPC64:
+		if f.ByteOrder == binary.LittleEndian {
+			return "ppc64le"
+		}
+		return "ppc64"
+	case elf.EM_RISCV:
+		return "riscv64"
+	case elf.EM_S390:
+		return "s390x"
+	}
+	return strings.ToLower(strings.TrimPrefix(f.Machine.String(), "EM_"))
+}
+
+// peFormatInfo 收集PE文件的格式信息
+func peFormatInfo(f *pe.File) *FormatInfo {
+	info := &FormatInfo{
+		Type:            FormatPE,
+		Bits:            32,
+		Endian:          "little",
+		SymbolsStripped: len(f.Symbols) == 0,
+		DWARFStripped:   f.Section(".debug_info") == nil && f.Section(".zdebug_info") == nil,
+	}
+
+	switch f.Machine {
+	case pe.IMAGE_FILE_MACHINE_I386:
+		info.Arch = "386"
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		info.Arch = "amd64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		info.Arch = "arm"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		info.Arch = "arm64"
+	default:
+		info.Arch = fmt.Sprintf("0x%x", f.Machine)
+	}
+
+	var dllCharacteristics uint16
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dllCharacteristics = oh.DllCharacteristics
+	case *pe.OptionalHeader64:
+		info.Bits = 64
+		dllCharacteristics = oh.DllCharacteristics
+	}
+	info.PIE = dllCharacteristics&pe.IMAGE_DLLCHARACTERISTICS_DYNAMIC_BASE != 0
+	info.Shared = f.Characteristics&pe.IMAGE_FILE_DLL != 0
+
+	// debug/pe 没有实现 ImportedLibraries，导入的DLL名称从导入符号（"函数名:DLL名"）中提取
+	if symbols, err := f.ImportedSymbols(); err == nil {
+		seen := make(map[string]bool)
+		for _, sym := range symbols {
+			if i := strings.LastIndexByte(sym, ':'); i >= 0 {
+				dll := strings.ToLower(sym[i+1:])
+				if !seen[dll] {
+					seen[dll] = true
+					info.Libraries = append(info.Libraries, dll)
+				}
+			}
+		}
+		sort.Strings(info.Libraries)
+	}
+	info.Static = len(info.Libraries) == 0
+
+	return info
+}
+
+// machoFormatInfo 收集Mach-O文件的格式信息
+func machoFormatInfo(f *macho.File) *FormatInfo {
+	info := &FormatInfo{
+		Type:            FormatMachO,
+		Bits:            32,
+		Endian:          endianName(f.ByteOrder),
+		SymbolsStripped: f.Symtab == nil || len(f.Symtab.Syms) == 0,
+		DWARFStripped:   f.Section("__debug_info") == nil && f.Section("__zdebug_info") == nil,
+		PIE:             f.Flags&macho.FlagPIE != 0,
+		Shared:          f.Type == macho.TypeDylib || f.Type == macho.TypeBundle,
+	}
+	if f.Magic == macho.Magic64 {
+		info.Bits = 64
+	}
+
+	info.Arch = machoArch(f.Cpu)
+
+	// LC_LOAD_DYLINKER 记录了动态链接器的路径
+	const loadCmdLoadDylinker = 0xe
+	for _, load := range f.Loads {
+		raw := load.Raw()
+		if len(raw) < 12 || f.ByteOrder.Uint32(raw) != loadCmdLoadDylinker {
+			continue
+		}
+		if offset := f.ByteOrder.Uint32(raw[8:]); int(offset) < len(raw) {
+			info.Interpreter = strings.TrimRight(string(raw[offset:]), "\x00")
+		}
+	}
+
+	if libs, err := f.ImportedLibraries(); err == nil {
+		info.Libraries = libs
+	}
+	info.Static = f.Type != macho.TypeObj && info.Interpreter == "" && len(info.Libraries) == 0
+
+	return info
+}
+
+// machoArch 将Mach-O的CPU类型转换为GOARCH风格的架构名称
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.Cpu386:
+		return "386"
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.CpuArm:
+		return "arm"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.CpuPpc:
+		return "ppc"
+	case macho.CpuPpc64:
+		return "ppc64"
+	}
+	return fmt.Sprintf("0x%x", uint32(cpu))
+}
+
+// wasmFormatInfo 收集WebAssembly模块的格式信息，动态库列表为导入段中引用的模块名
+func wasmFormatInfo(r io.ReaderAt) (*FormatInfo, error) {
+	info := &FormatInfo{
+		Type:            FormatWasm,
+		Arch:            "wasm",
+		Bits:            32,
+		Endian:          "little",
+		SymbolsStripped: true,
+		DWARFStripped:   true,
+		Static:          true,
+	}
+
+	err := forEachWasmSection(r, func(id byte, section *bufio.Reader) error {
+		switch id {
+		case wasmCustomSection:
+			name, err := readWasmName(section)
+			if err != nil {
+				return err
+			}
+			switch name {
+			case "name":
+				info.SymbolsStripped = false
+			case ".debug_info":
+				info.DWARFStripped = false
+			}
+		case wasmImportSection:
+			modules, err := readWasmImportModules(section)
+			if err != nil {
+				return err
+			}
+			info.Libraries = modules
+			info.Static = len(modules) == 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// wasm段类型
+const (
+	wasmCustomSection = 0
+	wasmImportSection = 2
+)
+
+// forEachWasmSection 依次读取wasm模块的每个段并调用fn，
+// fn中未读取完的段内容会被自动跳过。
+func forEachWasmSection(r io.ReaderAt, fn func(id byte, section *bufio.Reader) error) error {
+	br := bufio.NewReader(io.NewSectionReader(r, 8, 1<<62))
+	for {
+		id, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取wasm段失败: %w", err)
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return fmt.Errorf("读取wasm段失败: %w", err)
+		}
+
+		section := bufio.NewReader(io.LimitReader(br, int64(size)))
+		if err := fn(id, section); err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, section); err != nil {
+			return fmt.Errorf("读取wasm段失败: %w", err)
+		}
+	}
+}
+
+// readWasmImportModules 读取wasm导入段并返回去重后的模块名列表
+func readWasmImportModules(br *bufio.Reader) ([]string, error) {
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("读取wasm导入段失败: %w", err)
+	}
+
+	var modules []string
+	seen := make(map[string]bool)
+	for i := uint64(0); i < count; i++ {
+		module, err := readWasmName(br)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := readWasmName(br); err != nil {
+			return nil, err
+		}
+		if err := skipWasmImportDesc(br); err != nil {
+			return nil, err
+		}
+		if !seen[module] {
+			seen[module] = true
+			modules = append(modules, module)
+		}
+	}
+	return modules, nil
+}
+
+// skipWasmImportDesc 跳过一个wasm导入描述符
+func skipWasmImportDesc(br *bufio.Reader) error {
+	kind, err := br.ReadByte()
+	if err != nil {
+		return fmt.Errorf("读取wasm导入段失败: %w", err)
+	}
+
+	readLimits := func() error {
+		flags, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if _, err := binary.ReadUvarint(br); err != nil {
+			return err
+		}
+		if flags&1 != 0 {
+			_, err = binary.ReadUvarint(br)
+		}
+		return err
+	}
+
+	switch kind {
+	case 0: // 函数：类型索引
+		_, err = binary.ReadUvarint(br)
+	case 1: // 表：元素类型和大小限制
+		if _, err = br.ReadByte(); err == nil {
+			err = readLimits()
+		}
+	case 2: // 内存：大小限制
+		err = readLimits()
+	case 3: // 全局变量：值类型和可变性
+		if _, err = br.ReadByte(); err == nil {
+			_, err = br.ReadByte()
+		}
+	case 4: // 异常标签：属性和类型索引
+		if _, err = br.ReadByte(); err == nil {
+			_, err = binary.ReadUvarint(br)
+		}
+	default:
+		return fmt.Errorf("未知的wasm导入类型: %d", kind)
+	}
+	if err != nil {
+		return fmt.Errorf("读取wasm导入段失败: %w", err)
+	}
+	return nil
+}
+
+// maxWasmNameSize 是wasm名称长度的上限，长度前缀超过上限时视为文件损坏
+const maxWasmNameSize = 64 << 10
+
+// readWasmName 读取一个以长度为前缀的wasm名称
+func readWasmName(br *bufio.Reader) (string, error) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return "", fmt.Errorf("读取wasm名称失败: %w", err)
+	}
+	if n > maxWasmNameSize {
+		return "", fmt.Errorf("wasm名称长度 %d 无效", n)
+	}
+	name := make([]byte, n)
+	if _, err := io.ReadFull(br, name); err != nil {
+		return "", fmt.Errorf("读取wasm名称失败: %w", err)
+	}
+	return string(name), nil
+}
+
+// endianName 返回字节序的名称
+func endianName(order binary.ByteOrder) string {
+	if order == binary.BigEndian {
+		return "big"
+	}
+	return "little"
+}
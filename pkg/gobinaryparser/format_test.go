@@ -0,0 +1,99 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	f, err := os.Open(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("Failed to open test binary: %v", err)
+	}
+	defer f.Close()
+
+	format, err := DetectFormat(f)
+	if err != nil {
+		t.Fatalf("DetectFormat failed: %v", err)
+	}
+
+	wantType := map[string]string{"linux": FormatELF, "windows": FormatPE, "darwin": FormatMachO}[runtime.GOOS]
+	if wantType != "" && format.Type != wantType {
+		t.Errorf("Expected format %s, got %s", wantType, format.Type)
+	}
+	if format.Arch != runtime.GOARCH {
+		t.Errorf("Expected arch %s, got %s", runtime.GOARCH, format.Arch)
+	}
+	if format.Bits != 32 && format.Bits != 64 {
+		t.Errorf("Expected 32 or 64 bits, got %d", format.Bits)
+	}
+	if format.Endian != "little" && format.Endian != "big" {
+		t.Errorf("Unexpected endianness %q", format.Endian)
+	}
+}
+
+func TestDetectFormat_Invalid(t *testing.T) {
+	_, err := DetectFormat(bytes.NewReader([]byte("This is not a Go binary")))
+	if err == nil {
+		t.Error("Expected error for unrecognized format, got nil")
+	}
+}
+
+func TestDetectFormat_Wasm(t *testing.T) {
+	module := []byte("\x00asm\x01\x00\x00\x00")
+	// import section: 2 imports, "gojs"."runtime.wasmExit" (func 0) and "gojs"."mem" (memory min 1)
+	imports := []byte{0x02,
+		0x04, 'g', 'o', 'j', 's', 0x10, 'r', 'u', 'n', 't', 'i', 'm', 'e', '.', 'w', 'a', 's', 'm', 'E', 'x', 'i', 't', 0x00, 0x00,
+		0x04, 'g', 'o', 'j', 's', 0x03, 'm', 'e', 'm', 0x02, 0x00, 0x01,
+	}
+	module = append(module, 0x02, byte(len(imports)))
+	module = append(module, imports...)
+	// custom "name" section
+	module = append(module, 0x00, 0x05, 0x04, 'n', 'a', 'm', 'e')
+
+	format, err := DetectFormat(bytes.NewReader(module))
+	if err != nil {
+		t.Fatalf("DetectFormat failed: %v", err)
+	}
+	if format.Type != FormatWasm || format.Arch != "wasm" {
+		t.Errorf("Expected wasm format, got %+v", format)
+	}
+	if !reflect.DeepEqual(format.Libraries, []string{"gojs"}) {
+		t.Errorf("Expected imported module gojs, got %v", format.Libraries)
+	}
+	if format.Static {
+		t.Error("Expected module with imports not to be static")
+	}
+	if format.SymbolsStripped {
+		t.Error("Expected module with name section not to be stripped")
+	}
+}
+
+func TestDetectFormat_WasmInvalidNameLength(t *testing.T) {
+	module := []byte("\x00asm\x01\x00\x00\x00")
+	// custom section whose name length prefix is 2^64-1
+	module = append(module, 0x00, 0x0b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 'x')
+
+	if _, err := DetectFormat(bytes.NewReader(module)); err == nil {
+		t.Error("Expected error for an oversized wasm name")
+	}
+	if _, err := ReadBuildID(bytes.NewReader(module)); err == nil {
+		t.Error("Expected ReadBuildID error for an oversized wasm name")
+	}
+}
+
+func TestParseBinary_Format(t *testing.T) {
+	info, err := ParseBinary(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("ParseBinary failed: %v", err)
+	}
+	if info.Format == nil {
+		t.Fatal("Expected format information, got nil")
+	}
+	if info.Format.Arch != runtime.GOARCH {
+		t.Errorf("Expected arch %s, got %s", runtime.GOARCH, info.Format.Arch)
+	}
+}
@@ -0,0 +1,146 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoMod 根据构建信息重建 go.mod 文件，用于在源码丢失时重新构建二进制文件。
+// 生成的文件包含 module 指令、由Go版本推导的 go 指令、所有依赖的 require 指令以及 replace 指令。
+// 构建信息中不区分直接依赖和间接依赖，所有依赖都放在同一个 require 块中，重建源码后可以运行 go mod tidy 整理。
+//
+// 返回:
+//   - []byte: go.mod 文件内容
+//   - error: 如果缺少主模块路径（例如不是由解析函数返回的BinaryInfo），则返回错误信息
+//
+// 使用示例:
+//
+//	gomod, err := info.GoMod()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("go.mod", gomod, 0o644)
+func (info *BinaryInfo) GoMod() ([]byte, error) {
+	if info.modulePath == "" {
+		return nil, fmt.Errorf("构建信息中没有主模块路径，无法生成go.mod")
+	}
+
+	var buf bytes.Buffer
+	if info.FilePath != "" {
+		fmt.Fprintf(&buf, "// Reconstructed from the build info of %s\n", info.FilePath)
+	}
+	if info.Degraded != nil {
+		fmt.Fprintf(&buf, "// The build info is missing, dependencies were recovered heuristically and may be incomplete\n")
+	}
+	fmt.Fprintf(&buf, "module %s\n", modQuote(info.modulePath))
+	if goVersion := goDirective(info.GoVersion); goVersion != "" {
+		fmt.Fprintf(&buf, "\ngo %s\n", goVersion)
+	}
+
+	deps := make([]DependencyInfo, 0, len(info.Dependencies))
+	for _, dep := range info.Dependencies {
+		if dep.Path != "" && dep.Version != "" {
+			deps = append(deps, dep)
+		}
+	}
+	SortDependencies(deps)
+
+	if len(deps) > 0 {
+		buf.WriteString("\nrequire (\n")
+		for _, dep := range deps {
+			fmt.Fprintf(&buf, "\t%s %s\n", modQuote(dep.Path), modQuote(dep.Version))
+		}
+		buf.WriteString(")\n")
+	}
+
+	var replaces []DependencyInfo
+	for _, dep := range deps {
+		if dep.Replace != nil {
+			replaces = append(replaces, dep)
+		}
+	}
+	if len(replaces) > 0 {
+		buf.WriteString("\nreplace (\n")
+		for _, dep := range replaces {
+			target := modQuote(dep.Replace.Path)
+			// 替换为本地目录时构建信息中的版本为 (devel)，go.mod 中的目录替换不能带版本
+			if dep.ReplaceKind() != ReplaceLocal {
+				target += " " + modQuote(dep.Replace.Version)
+			}
+			fmt.Fprintf(&buf, "\t%s %s => %s\n", modQuote(dep.Path), modQuote(dep.Version), target)
+		}
+		buf.WriteString(")\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GoSum 根据构建信息中的校验和生成 go.sum 文件。
+// 构建信息只记录模块内容的校验和，不包含 go.mod 文件的校验和（"/go.mod" 行），
+// 这些行会在第一次构建时由go命令补全。没有校验和的依赖（例如替换为本地目录的依赖）会被跳过。
+//
+// 返回:
+//   - []byte: go.sum 文件内容，没有任何校验和时为空
+//
+// 使用示例:
+//
+//	os.WriteFile("go.sum", info.GoSum(), 0o644)
+func (info *BinaryInfo) GoSum() []byte {
+	seen := make(map[string]bool)
+	var lines []string
+	add := func(m *DependencyInfo) {
+		if m.Path == "" || m.Version == "" || m.Sum == "" {
+			return
+		}
+		line := fmt.Sprintf("%s %s %s\n", m.Path, m.Version, m.Sum)
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	for i := range info.Dependencies {
+		dep := &info.Dependencies[i]
+		add(dep)
+		if dep.Replace != nil {
+			add(dep.Replace)
+		}
+	}
+
+	// go.sum 按模块路径和版本排序
+	sort.Slice(lines, func(i, j int) bool {
+		pi, vi, _ := strings.Cut(lines[i], " ")
+		pj, vj, _ := strings.Cut(lines[j], " ")
+		if pi != pj {
+			return pi < pj
+		}
+		return CompareVersions(strings.Fields(vi)[0], strings.Fields(vj)[0]) < 0
+	})
+	return []byte(strings.Join(lines, ""))
+}
+
+// goDirective 将Go版本转换为 go 指令的版本：Go 1.21起包含修订号，例如 "1.22.3"，之前为 "1.20"。
+// 无法识别的版本返回空字符串，此时省略 go 指令。
+func goDirective(goVersion string) string {
+	v, err := ParseGoVersion(goVersion)
+	if err != nil || v.Major == 0 {
+		return ""
+	}
+	if v.Major == 1 && v.Minor < 21 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	if v.Prerelease != "" {
+		return fmt.Sprintf("%d.%d%s", v.Major, v.Minor, v.Prerelease)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// modQuote 在模块路径或版本包含空白或特殊字符时为其加上引号
+func modQuote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"'`()[]{},") || strings.Contains(s, "//") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package gobinaryparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBinaryInfo_GoMod(t *testing.T) {
+	info := &BinaryInfo{
+		modulePath: "github.com/example/app",
+		GoVersion:  "go1.22.3 X:boringcrypto",
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/spf13/cobra", Version: "v1.6.1", Sum: "h1:cobra"},
+			{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../my b", Version: "(devel)"}},
+			{Path: "github.com/c/d", Version: "v1.2.0", Replace: &DependencyInfo{Path: "github.com/fork/d", Version: "v1.2.1", Sum: "h1:fork"}},
+		},
+	}
+
+	got, err := info.GoMod()
+	if err != nil {
+		t.Fatalf("GoMod() error = %v", err)
+	}
+	want := `module github.com/example/app
+
+go 1.22.3
+
+require (
+	github.com/a/b v1.0.0
+	github.com/c/d v1.2.0
+	github.com/spf13/cobra v1.6.1
+)
+
+replace (
+	github.com/a/b v1.0.0 => "../my b"
+	github.com/c/d v1.2.0 => github.com/fork/d v1.2.1
+)
+`
+	if string(got) != want {
+		t.Errorf("GoMod() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := (&BinaryInfo{}).GoMod(); err == nil {
+		t.Error("Expected error without a module path")
+	}
+}
+
+func TestBinaryInfo_GoSum(t *testing.T) {
+	info := &BinaryInfo{
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/spf13/cobra", Version: "v1.6.1", Sum: "h1:cobra"},
+			{Path: "github.com/a/b", Version: "v1.10.0", Sum: "h1:b10"},
+			{Path: "github.com/a/b", Version: "v1.9.0", Sum: "h1:b9"},
+			{Path: "github.com/c/d", Version: "v1.2.0", Replace: &DependencyInfo{Path: "github.com/fork/d", Version: "v1.2.1", Sum: "h1:fork"}},
+			{Path: "github.com/e/f", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../f", Version: "(devel)"}},
+		},
+	}
+
+	want := strings.Join([]string{
+		"github.com/a/b v1.9.0 h1:b9",
+		"github.com/a/b v1.10.0 h1:b10",
+		"github.com/fork/d v1.2.1 h1:fork",
+		"github.com/spf13/cobra v1.6.1 h1:cobra",
+	}, "\n") + "\n"
+	if got := string(info.GoSum()); got != want {
+		t.Errorf("GoSum() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoDirective(t *testing.T) {
+	tests := map[string]string{
+		"go1.20.14":             "1.20",
+		"go1.21.0":              "1.21.0",
+		"go1.22rc1":             "1.22rc1",
+		"devel go1.23-abc123 x": "1.23.0",
+		"devel +abc123":         "",
+		"":                      "",
+	}
+	for in, want := range tests {
+		if got := goDirective(in); got != want {
+			t.Errorf("goDirective(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGoMod_TestBinary(t *testing.T) {
+	info, err := ParseBinaryFromFile(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("ParseBinaryFromFile() error = %v", err)
+	}
+	if info.modulePath == "" {
+		t.Skip("test binary has no module path")
+	}
+	gomod, err := info.GoMod()
+	if err != nil {
+		t.Fatalf("GoMod() error = %v", err)
+	}
+	if !strings.Contains(string(gomod), "module "+info.modulePath+"\n") {
+		t.Errorf("GoMod() missing module directive:\n%s", gomod)
+	}
+}
@@ -0,0 +1,259 @@
+package gobinaryparser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// goReleasePattern 匹配Go发布版本，例如 go1.22.3、go1.21rc2、go1.9beta1
+var goReleasePattern = regexp.MustCompile(`^go(\d+)(?:\.(\d+))?(?:\.(\d+))?((?:alpha|beta|rc)\d+)?$`)
+
+// ParseGoVersion 解析 runtime.Version() 格式的Go版本字符串。
+// 支持发布版本（"go1.22.3"）、预发布版本（"go1.21rc2"）、带实验特性的版本（"go1.22.3 X:boringcrypto"）
+// 以及开发版本（"devel go1.23-abc123 Tue Jan 2 15:04:05 2024 +0000"、"devel +abc123"）。
+//
+// 参数:
+//   - s: Go版本字符串，通常来自 BinaryInfo.GoVersion
+//
+// 返回:
+//   - GoVersion: 解析后的版本
+//   - error: 如果字符串不是可识别的Go版本，则返回错误信息
+//
+// 使用示例:
+//
+//	v, err := gobinaryparser.ParseGoVersion("go1.22.3 X:boringcrypto")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(v.Minor, v.Patch, v.Experiments) // 22 3 [boringcrypto]
+func ParseGoVersion(s string) (GoVersion, error) {
+	v := GoVersion{Raw: s}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return v, fmt.Errorf("Go版本为空")
+	}
+	for _, field := range fields[1:] {
+		if exp, ok := strings.CutPrefix(field, "X:"); ok {
+			v.Experiments = append(v.Experiments, splitList(exp, ",")...)
+		}
+	}
+
+	version := fields[0]
+	if version == "devel" {
+		v.Devel = true
+		if len(fields) < 2 {
+			return v, nil
+		}
+		// 新式开发版本为 "devel go1.23-abc123 ..."，旧式为 "devel +abc123 ..."
+		if commit, ok := strings.CutPrefix(fields[1], "+"); ok {
+			v.DevelCommit = commit
+			return v, nil
+		}
+		if !strings.HasPrefix(fields[1], "go") {
+			return v, nil
+		}
+		version, v.DevelCommit, _ = strings.Cut(fields[1], "-")
+	}
+
+	m := goReleasePattern.FindStringSubmatch(version)
+	if m == nil {
+		return v, fmt.Errorf("无法识别的Go版本: %q", s)
+	}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	v.Prerelease = m[4]
+	return v, nil
+}
+
+// String 返回原始版本字符串，没有原始字符串时按发布版本格式生成
+func (v GoVersion) String() string {
+	if v.Raw != "" {
+		return v.Raw
+	}
+	s := fmt.Sprintf("go%d.%d", v.Major, v.Minor)
+	switch {
+	case v.Prerelease != "":
+		s += v.Prerelease
+	case v.Patch > 0 || v.Major > 1 || v.Minor >= 21:
+		// Go 1.21起第一个正式版本为 go1.21.0
+		s += fmt.Sprintf(".%d", v.Patch)
+	}
+	if v.Devel {
+		s = "devel " + s
+		if v.DevelCommit != "" {
+			s += "-" + v.DevelCommit
+		}
+	}
+	return s
+}
+
+// Compare 比较两个版本，v小于、等于、大于other时分别返回-1、0、1。
+// 主版本号、次版本号和修订号相同时，开发版本低于预发布版本，预发布版本（alpha < beta < rc）低于正式版本；
+// 开发版本的提交哈希和实验特性不参与比较。
+//
+// 参数:
+//   - other: 要比较的版本
+//
+// 返回:
+//   - int: 比较结果
+//
+// 使用示例:
+//
+//	a, _ := gobinaryparser.ParseGoVersion("go1.21rc2")
+//	b, _ := gobinaryparser.ParseGoVersion("go1.21.0")
+//	fmt.Println(a.Compare(b)) // -1
+func (v GoVersion) Compare(other GoVersion) int {
+	a, b := v.key(), other.key()
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Less 判断v是否低于other，等价于 v.Compare(other) < 0
+func (v GoVersion) Less(other GoVersion) bool {
+	return v.Compare(other) < 0
+}
+
+// key 将版本转换为可比较的数字序列：主版本号、次版本号、修订号、发布阶段、预发布序号
+func (v GoVersion) key() [5]int {
+	key := [5]int{v.Major, v.Minor, v.Patch, 4, 0}
+	switch {
+	case v.Devel && v.Prerelease == "":
+		key[3] = 0
+	case strings.HasPrefix(v.Prerelease, "alpha"):
+		key[3], key[4] = 1, atoiSuffix(v.Prerelease, "alpha")
+	case strings.HasPrefix(v.Prerelease, "beta"):
+		key[3], key[4] = 2, atoiSuffix(v.Prerelease, "beta")
+	case strings.HasPrefix(v.Prerelease, "rc"):
+		key[3], key[4] = 3, atoiSuffix(v.Prerelease, "rc")
+	}
+	return key
+}
+
+// atoiSuffix 返回去掉前缀后的数字
+func atoiSuffix(s, prefix string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(s, prefix))
+	return n
+}
+
+// GoVersionInfo 解析二进制文件的Go版本。
+//
+// 返回:
+//   - GoVersion: 解析后的版本
+//   - error: 如果GoVersion为空或无法识别，则返回错误信息
+//
+// 使用示例:
+//
+//	v, err := info.GoVersionInfo()
+//	if err == nil && v.Less(minimum) {
+//		fmt.Printf("%s 使用了过旧的Go版本 %s\n", info.FilePath, v)
+//	}
+func (info *BinaryInfo) GoVersionInfo() (GoVersion, error) {
+	return ParseGoVersion(info.GoVersion)
+}
+
+// GoVersionConstraint 表示对Go版本的约束，由逗号分隔的多个比较条件组成，所有条件都满足时版本满足约束，
+// 例如 "<go1.21.9" 或 ">=1.21, <1.22.5"
+type GoVersionConstraint struct {
+	raw   string
+	terms []goVersionTerm
+}
+
+// goVersionTerm 是约束中的一个比较条件
+type goVersionTerm struct {
+	op      string
+	version GoVersion
+}
+
+// comparisonOperators 按匹配优先级排列的比较运算符
+var comparisonOperators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// splitComparison 拆分比较条件中的运算符和版本，省略运算符时为"="，"=="等价于"="
+func splitComparison(term string) (op string, version string) {
+	for _, op := range comparisonOperators {
+		if rest, ok := strings.CutPrefix(term, op); ok {
+			if op == "==" {
+				op = "="
+			}
+			return op, strings.TrimSpace(rest)
+		}
+	}
+	return "=", term
+}
+
+// ParseGoVersionConstraint 解析Go版本约束。
+// 每个条件由运算符（<、<=、>、>=、=、==、!=，省略时为=）和版本组成，版本可以省略 "go" 前缀。
+//
+// 参数:
+//   - s: 约束字符串
+//
+// 返回:
+//   - *GoVersionConstraint: 解析后的约束
+//   - error: 如果约束为空或包含无法识别的版本，则返回错误信息
+//
+// 使用示例:
+//
+//	c, err := gobinaryparser.ParseGoVersionConstraint("<go1.21.9")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if v, err := info.GoVersionInfo(); err == nil && c.Check(v) {
+//		fmt.Printf("%s 需要使用新版本Go重新编译\n", info.FilePath)
+//	}
+func ParseGoVersionConstraint(s string) (*GoVersionConstraint, error) {
+	c := &GoVersionConstraint{raw: s}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		term := goVersionTerm{}
+		term.op, part = splitComparison(part)
+		if !strings.HasPrefix(part, "go") {
+			part = "go" + part
+		}
+
+		v, err := ParseGoVersion(part)
+		if err != nil {
+			return nil, fmt.Errorf("无效的Go版本约束 %q: %w", s, err)
+		}
+		term.version = v
+		c.terms = append(c.terms, term)
+	}
+	if len(c.terms) == 0 {
+		return nil, fmt.Errorf("Go版本约束为空")
+	}
+	return c, nil
+}
+
+// Check 判断版本是否满足约束
+//
+// 参数:
+//   - v: 要检查的版本
+//
+// 返回:
+//   - bool: 满足所有条件时返回true
+func (c *GoVersionConstraint) Check(v GoVersion) bool {
+	for _, term := range c.terms {
+		if !compareOp(term.op, v.Compare(term.version)) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回约束的原始字符串
+func (c *GoVersionConstraint) String() string {
+	return c.raw
+}
@@ -0,0 +1,124 @@
+package gobinaryparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGoVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want GoVersion
+	}{
+		{"go1.22.3", GoVersion{Major: 1, Minor: 22, Patch: 3}},
+		{"go1.21rc2", GoVersion{Major: 1, Minor: 21, Prerelease: "rc2"}},
+		{"go1.9", GoVersion{Major: 1, Minor: 9}},
+		{"go1.22.3 X:boringcrypto", GoVersion{Major: 1, Minor: 22, Patch: 3, Experiments: []string{"boringcrypto"}}},
+		{"go1.22.3 X:boringcrypto,loopvar", GoVersion{Major: 1, Minor: 22, Patch: 3, Experiments: []string{"boringcrypto", "loopvar"}}},
+		{"devel go1.23-abc123 Tue Jan 2 15:04:05 2024 +0000", GoVersion{Major: 1, Minor: 23, Devel: true, DevelCommit: "abc123"}},
+		{"devel go1.23-abc123 Tue Jan 2 15:04:05 2024 +0000 X:rangefunc", GoVersion{Major: 1, Minor: 23, Devel: true, DevelCommit: "abc123", Experiments: []string{"rangefunc"}}},
+		{"devel +b7a85e0003 Fri Aug 17 00:06:01 2018 +0000", GoVersion{Devel: true, DevelCommit: "b7a85e0003"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseGoVersion(tt.in)
+		if err != nil {
+			t.Errorf("ParseGoVersion(%q) error = %v", tt.in, err)
+			continue
+		}
+		tt.want.Raw = tt.in
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseGoVersion(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "1.22", "gopher", "go1.x"} {
+		if _, err := ParseGoVersion(in); err == nil {
+			t.Errorf("ParseGoVersion(%q) expected error", in)
+		}
+	}
+}
+
+func TestGoVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"go1.11", "go1.11.13", -1},
+		{"go1.12rc1", "go1.12", -1},
+		{"go1.12beta2", "go1.12rc1", -1},
+		{"go1.9.7", "go1.10", -1},
+		{"go1.21.0", "go1.21.0", 0},
+		{"go1.21", "go1.21.0", 0},
+		{"go1.22.1", "go1.21.9", 1},
+		{"go1.22.3 X:boringcrypto", "go1.22.3", 0},
+		{"devel go1.23-abc123", "go1.23rc1", -1},
+		{"devel go1.23-abc123", "go1.22.5", 1},
+		{"devel +abc123", "go1.9", -1},
+	}
+	for _, tt := range tests {
+		a, _ := ParseGoVersion(tt.a)
+		b, _ := ParseGoVersion(tt.b)
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Compare(a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+		if a.Less(b) != (tt.want < 0) {
+			t.Errorf("Less(%q, %q) = %v", tt.a, tt.b, a.Less(b))
+		}
+	}
+}
+
+func TestGoVersion_String(t *testing.T) {
+	tests := []struct {
+		v    GoVersion
+		want string
+	}{
+		{GoVersion{Major: 1, Minor: 20, Patch: 3}, "go1.20.3"},
+		{GoVersion{Major: 1, Minor: 20}, "go1.20"},
+		{GoVersion{Major: 1, Minor: 21}, "go1.21.0"},
+		{GoVersion{Major: 1, Minor: 22, Prerelease: "rc1"}, "go1.22rc1"},
+		{GoVersion{Raw: "go1.22.3 X:boringcrypto", Major: 1, Minor: 22, Patch: 3}, "go1.22.3 X:boringcrypto"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestGoVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"<go1.21.9", "go1.21.8", true},
+		{"<go1.21.9", "go1.21.9", false},
+		{"<1.21.9", "go1.21rc2", true},
+		{">=1.21, <1.22.5", "go1.22.3 X:boringcrypto", true},
+		{">=1.21, <1.22.5", "go1.22.5", false},
+		{"go1.22.3", "go1.22.3", true},
+		{"==go1.22.3", "go1.22.4", false},
+		{"!=1.22.3", "go1.22.4", true},
+		{">go1.22", "devel go1.23-abc123", true},
+	}
+	for _, tt := range tests {
+		c, err := ParseGoVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseGoVersionConstraint(%q) error = %v", tt.constraint, err)
+			continue
+		}
+		v, _ := ParseGoVersion(tt.version)
+		if got := c.Check(v); got != tt.want {
+			t.Errorf("%q.Check(%q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", " , ", "<gopher", ">=1.21, <x"} {
+		if _, err := ParseGoVersionConstraint(in); err == nil {
+			t.Errorf("ParseGoVersionConstraint(%q) expected error", in)
+		}
+	}
+}
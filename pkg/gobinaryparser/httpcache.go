@@ -0,0 +1,413 @@
+package gobinaryparser
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// HTTPReaderAt 块缓存的默认配置
+const (
+	DefaultHTTPBlockSize = 64 << 10 // 默认块大小，64 KiB
+	DefaultHTTPReadahead = 2        // 默认每次请求额外预读的块数
+	DefaultHTTPMaxBlocks = 256      // 默认缓存的最大块数，与默认块大小相乘为16 MiB
+)
+
+// HTTPCacheOptions 控制 HTTPReaderAt 的块缓存。
+// 远程文件按BlockSize对齐划分为块，ReadAt只请求缓存中缺失的块，连续缺失的块合并为一个范围请求，
+// 并在其后预读Readahead个块。多个goroutine同时读取相同的块时只发送一次请求。
+type HTTPCacheOptions struct {
+	BlockSize int64 // 块大小，<=0 时使用 DefaultHTTPBlockSize
+	Readahead int   // 每次请求在缺失的块之后额外预读的块数，0 时使用 DefaultHTTPReadahead，<0 表示不预读
+	MaxBlocks int   // 缓存的最大块数，超过时淘汰最久未使用的块，<=0 时使用 DefaultHTTPMaxBlocks
+}
+
+// normalize 返回将未设置的字段替换为默认值后的配置
+func (o HTTPCacheOptions) normalize() HTTPCacheOptions {
+	if o.BlockSize <= 0 {
+		o.BlockSize = DefaultHTTPBlockSize
+	}
+	switch {
+	case o.Readahead == 0:
+		o.Readahead = DefaultHTTPReadahead
+	case o.Readahead < 0:
+		o.Readahead = 0
+	}
+	if o.MaxBlocks <= 0 {
+		o.MaxBlocks = DefaultHTTPMaxBlocks
+	}
+	return o
+}
+
+// HTTPReaderStats 表示 HTTPReaderAt 的读取统计
+// 示例：
+//
+//	{
+//	  "requests": 3,
+//	  "bytes_fetched": 589824,
+//	  "cache_hits": 41,
+//	  "cache_misses": 5,
+//	  "coalesced": 0,
+//	  "full_download": false
+//	}
+type HTTPReaderStats struct {
+	Requests     int64 `json:"requests"`      // 发送的HTTP请求数量（不含重试），包括HEAD探测请求
+	BytesFetched int64 `json:"bytes_fetched"` // 从服务器接收的字节数
+	CacheHits    int64 `json:"cache_hits"`    // 直接从缓存读取的块数
+	CacheMisses  int64 `json:"cache_misses"`  // ReadAt需要但不在缓存中、因此发送了请求的块数（不含预读的块）
+	Coalesced    int64 `json:"coalesced"`     // 等待其他goroutine正在进行的请求而没有重复请求的块数
+	FullDownload bool  `json:"full_download"` // 服务器不支持范围请求，已下载整个文件
+}
+
+// errResponseInterrupted 表示范围请求的响应体在读取过程中中断，剩余的部分可以重新请求
+var errResponseInterrupted = errors.New("响应中断")
+
+// blockCache 是 HTTPReaderAt 的块缓存，由同一个HTTPReaderAt通过 WithContext 派生的所有读取器共享
+type blockCache struct {
+	opts HTTPCacheOptions
+
+	mu       sync.Mutex
+	blocks   map[int64]*list.Element // 块序号到LRU链表元素的映射，元素的值为 *cachedBlock
+	lru      *list.List
+	inflight map[int64]*blockFetch // 正在请求的块
+	size     int64                 // 远程文件的大小，未知时为-1
+	err      error                 // 最近一次读取失败的错误
+
+	// 范围请求的验证状态，参见 httprange.go
+	probeOnce    sync.Once
+	probeErr     error
+	rangesOK     bool         // 服务器已经返回过有效的206响应
+	noRanges     bool         // HEAD响应声明了 Accept-Ranges: none
+	total        int64        // 服务器报告的文件大小，未知时为-1
+	etag         string       // 第一个响应的ETag
+	lastModified string       // 第一个响应的Last-Modified
+	full         *streamSpool // 服务器不支持范围请求时下载的完整文件
+	fullDownload atomic.Bool
+
+	requests, bytesFetched, hits, misses, coalesced atomic.Int64
+}
+
+// cachedBlock 是缓存中的一个块，文件末尾的块可能短于块大小
+type cachedBlock struct {
+	index int64
+	data  []byte
+}
+
+// blockFetch 表示一个正在进行的范围请求，完成后关闭done
+type blockFetch struct {
+	done   chan struct{}
+	blocks map[int64][]byte
+	err    error
+}
+
+// newBlockCache 创建使用给定配置的块缓存
+func newBlockCache(opts HTTPCacheOptions) *blockCache {
+	return &blockCache{
+		opts:     opts.normalize(),
+		blocks:   make(map[int64]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[int64]*blockFetch),
+		size:     -1,
+		total:    -1,
+	}
+}
+
+// stats 返回当前的读取统计
+func (c *blockCache) stats() HTTPReaderStats {
+	return HTTPReaderStats{
+		Requests:     c.requests.Load(),
+		BytesFetched: c.bytesFetched.Load(),
+		CacheHits:    c.hits.Load(),
+		CacheMisses:  c.misses.Load(),
+		Coalesced:    c.coalesced.Load(),
+		FullDownload: c.fullDownload.Load(),
+	}
+}
+
+// blockRun 表示一次范围请求覆盖的连续块[first, last]
+type blockRun struct {
+	first, last int64
+	fetch       *blockFetch
+}
+
+// readAt 从缓存读取p，缺失的块通过h发送范围请求获取
+func (c *blockCache) readAt(h *HTTPReaderAt, p []byte, off int64) (int, error) {
+	bs := c.opts.BlockSize
+	first := off / bs
+	last := (off + int64(len(p)) - 1) / bs
+	done := make([]bool, last-first+1)
+
+	if h.parser.headProbe {
+		c.probeOnce.Do(func() { c.probeErr = c.probe(h) })
+		if c.probeErr != nil {
+			return 0, c.probeErr
+		}
+	}
+
+	for {
+		c.mu.Lock()
+		if c.size >= 0 && off >= c.size {
+			c.mu.Unlock()
+			return 0, io.EOF
+		}
+		if full := c.full; full != nil {
+			// 已经下载了整个文件，直接从中读取
+			c.mu.Unlock()
+			c.hits.Add(1)
+			return full.ReadAt(p, off)
+		}
+		var waits []*blockFetch
+		var missing []int64
+		for idx := first; idx <= last; idx++ {
+			if done[idx-first] {
+				continue
+			}
+			if c.size >= 0 && idx*bs >= c.size {
+				done[idx-first] = true
+				continue
+			}
+			if elem, ok := c.blocks[idx]; ok {
+				c.lru.MoveToFront(elem)
+				copyBlock(p, off, idx*bs, elem.Value.(*cachedBlock).data)
+				done[idx-first] = true
+				c.hits.Add(1)
+				continue
+			}
+			if f, ok := c.inflight[idx]; ok {
+				waits = append(waits, f)
+				continue
+			}
+			missing = append(missing, idx)
+		}
+		if len(missing) == 0 && len(waits) == 0 {
+			size := c.size
+			c.mu.Unlock()
+			if size >= 0 && off+int64(len(p)) > size {
+				return int(size - off), io.EOF
+			}
+			return len(p), nil
+		}
+		var runs []*blockRun
+		if len(missing) > 0 {
+			runs = c.planRuns(missing)
+		}
+		c.mu.Unlock()
+		c.misses.Add(int64(len(missing)))
+
+		var fetchErr error
+		for _, run := range runs {
+			if fetchErr != nil {
+				// 前一个请求失败时不再发送后续请求，但仍需唤醒等待这些块的goroutine
+				c.finish(run, nil, fetchErr)
+				continue
+			}
+			blocks, err := c.fetchRun(h, run)
+			c.finish(run, blocks, err)
+			if err != nil {
+				fetchErr = err
+				continue
+			}
+			c.copyFetched(p, off, first, done, blocks)
+		}
+		if fetchErr != nil {
+			return 0, fetchErr
+		}
+
+		for _, f := range waits {
+			select {
+			case <-f.done:
+			case <-h.ctx.Done():
+				return 0, h.ctx.Err()
+			}
+			if f.err != nil {
+				return 0, f.err
+			}
+			c.coalesced.Add(int64(c.copyFetched(p, off, first, done, f.blocks)))
+		}
+	}
+}
+
+// copyFetched 将请求得到的块中落在[first, ...]范围内且尚未读取的部分复制到p，返回复制的块数
+func (c *blockCache) copyFetched(p []byte, off, first int64, done []bool, blocks map[int64][]byte) int {
+	n := 0
+	for idx, data := range blocks {
+		i := idx - first
+		if i < 0 || i >= int64(len(done)) || done[i] {
+			continue
+		}
+		copyBlock(p, off, idx*c.opts.BlockSize, data)
+		done[i] = true
+		n++
+	}
+	return n
+}
+
+// planRuns 将缺失的块合并为连续的范围请求，在最后一个范围之后追加预读的块，并将这些块登记为正在请求。
+// 调用者必须持有c.mu。
+func (c *blockCache) planRuns(missing []int64) []*blockRun {
+	var runs []*blockRun
+	for _, idx := range missing {
+		if n := len(runs); n > 0 && runs[n-1].last+1 == idx {
+			runs[n-1].last = idx
+			continue
+		}
+		runs = append(runs, &blockRun{first: idx, last: idx})
+	}
+
+	tail := runs[len(runs)-1]
+	for i := 0; i < c.opts.Readahead; i++ {
+		next := tail.last + 1
+		if c.size >= 0 && next*c.opts.BlockSize >= c.size {
+			break
+		}
+		if _, ok := c.blocks[next]; ok {
+			break
+		}
+		if _, ok := c.inflight[next]; ok {
+			break
+		}
+		tail.last = next
+	}
+
+	for _, run := range runs {
+		run.fetch = &blockFetch{done: make(chan struct{})}
+		for idx := run.first; idx <= run.last; idx++ {
+			c.inflight[idx] = run.fetch
+		}
+	}
+	return runs
+}
+
+// finish 保存请求得到的块，并唤醒等待这些块的goroutine
+func (c *blockCache) finish(run *blockRun, blocks map[int64][]byte, err error) {
+	c.mu.Lock()
+	for idx := run.first; idx <= run.last; idx++ {
+		delete(c.inflight, idx)
+	}
+	for idx, data := range blocks {
+		c.put(idx, data)
+	}
+	c.mu.Unlock()
+
+	run.fetch.blocks = blocks
+	run.fetch.err = err
+	close(run.fetch.done)
+}
+
+// put 将块加入缓存，超过最大块数时淘汰最久未使用的块。调用者必须持有c.mu。
+func (c *blockCache) put(idx int64, data []byte) {
+	if elem, ok := c.blocks[idx]; ok {
+		elem.Value.(*cachedBlock).data = data
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.blocks[idx] = c.lru.PushFront(&cachedBlock{index: idx, data: data})
+	for c.lru.Len() > c.opts.MaxBlocks {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.blocks, oldest.Value.(*cachedBlock).index)
+	}
+}
+
+// fetchRun 发送范围请求获取连续的块，返回按块序号拆分的数据。
+// 服务器返回的范围比请求的短时，从下一个字节继续请求，直到填满整个范围或到达文件末尾；
+// 响应体在读取过程中中断时，按解析器的重试策略从中断的位置重新请求剩余的范围。
+func (c *blockCache) fetchRun(h *HTTPReaderAt, run *blockRun) (map[int64][]byte, error) {
+	bs := c.opts.BlockSize
+	start, end := run.first*bs, (run.last+1)*bs-1
+
+	data := make([]byte, end-start+1)
+	n := 0
+	for attempt := 1; n < len(data); {
+		m, eof, err := c.readRange(h, start+int64(n), data[n:])
+		n += m
+		if err == nil {
+			if eof {
+				c.setSize(start + int64(n))
+				break
+			}
+			if m == 0 {
+				return nil, fmt.Errorf("服务器对范围 %d-%d 返回了空的响应", start+int64(n), end)
+			}
+			continue
+		}
+		if !errors.Is(err, errResponseInterrupted) || h.ctx.Err() != nil {
+			return nil, err
+		}
+		if attempt >= h.parser.retry.attempts() {
+			return nil, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		wait := h.parser.retry.delay(attempt, nil)
+		h.parser.logger.Warn("读取范围请求的响应中断，稍后继续", "url", h.url, "offset", start+int64(n), "attempt", attempt, "error", err, "wait", wait)
+		if err := sleepContext(h.ctx, wait); err != nil {
+			return nil, &RetryError{Attempts: attempt, Err: err}
+		}
+		attempt++
+	}
+	data = data[:n]
+
+	blocks := make(map[int64][]byte)
+	for i := 0; i < n; i += int(bs) {
+		j := i + int(bs)
+		if j > n {
+			j = n
+		}
+		blocks[run.first+int64(i)/bs] = data[i:j:j]
+	}
+	return blocks, nil
+}
+
+// close 清空缓存的块并删除下载的完整文件
+func (c *blockCache) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks = make(map[int64]*list.Element)
+	c.lru.Init()
+	if c.full == nil {
+		return nil
+	}
+	err := c.full.Close()
+	c.full = nil
+	return err
+}
+
+// setReadErr 记录读取失败的错误
+func (c *blockCache) setReadErr(err error) {
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+// readErr 返回最近一次读取失败的错误
+func (c *blockCache) readErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// setSize 记录远程文件的大小
+func (c *blockCache) setSize(size int64) {
+	c.mu.Lock()
+	if c.size < 0 || size < c.size {
+		c.size = size
+	}
+	c.mu.Unlock()
+}
+
+// copyBlock 将从文件偏移blockOff开始的块数据中与[off, off+len(p))重叠的部分复制到p
+func copyBlock(p []byte, off, blockOff int64, data []byte) {
+	lo, hi := off, off+int64(len(p))
+	if blockOff > lo {
+		lo = blockOff
+	}
+	if end := blockOff + int64(len(data)); end < hi {
+		hi = end
+	}
+	if lo < hi {
+		copy(p[lo-off:hi-off], data[lo-blockOff:hi-blockOff])
+	}
+}
@@ -0,0 +1,184 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRangeServer 创建使用 http.ServeContent 返回data的测试服务器，请求到达时调用onRequest
+func newRangeServer(t *testing.T, data []byte, requests *int32, onRequest func()) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if onRequest != nil {
+			onRequest()
+		}
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPReaderAt_BlockCache(t *testing.T) {
+	var requests int32
+	server := newRangeServer(t, []byte("0123456789abcdef"), &requests, nil)
+
+	p := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 4, Readahead: 1}))
+	reader := p.NewHTTPReaderAt(server.URL)
+
+	buf := make([]byte, 3)
+	if n, err := reader.ReadAt(buf, 2); err != nil || string(buf[:n]) != "234" {
+		t.Fatalf("ReadAt(2) = %q, %v, want \"234\"", buf[:n], err)
+	}
+	// 块0和预读的块1在第一次请求中获取
+	if n, err := reader.ReadAt(buf, 5); err != nil || string(buf[:n]) != "567" {
+		t.Fatalf("ReadAt(5) = %q, %v, want \"567\"", buf[:n], err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d after reading two cached blocks, want 1", requests)
+	}
+
+	buf = make([]byte, 6)
+	n, err := reader.ReadAt(buf, 12)
+	if !errors.Is(err, io.EOF) || string(buf[:n]) != "cdef" {
+		t.Errorf("ReadAt(12) = %q, %v, want \"cdef\", io.EOF", buf[:n], err)
+	}
+	if _, err := reader.ReadAt(buf, 100); !errors.Is(err, io.EOF) {
+		t.Errorf("ReadAt(100) error = %v, want io.EOF", err)
+	}
+
+	stats := reader.Stats()
+	if stats.Requests != int64(requests) || stats.BytesFetched != 16 {
+		t.Errorf("Stats() = %+v, want %d requests and 16 bytes", stats, requests)
+	}
+	if stats.CacheHits != 1 || stats.CacheMisses != 3 {
+		t.Errorf("Stats() = %+v, want 1 hit and 3 misses", stats)
+	}
+}
+
+func TestHTTPReaderAt_Eviction(t *testing.T) {
+	var requests int32
+	server := newRangeServer(t, []byte("0123456789abcdef"), &requests, nil)
+
+	p := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 4, Readahead: -1, MaxBlocks: 1}))
+	reader := p.NewHTTPReaderAt(server.URL)
+
+	buf := make([]byte, 2)
+	for _, off := range []int64{0, 4, 0} {
+		if _, err := reader.ReadAt(buf, off); err != nil {
+			t.Fatalf("ReadAt(%d) error = %v", off, err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 with a single cached block", requests)
+	}
+}
+
+func TestHTTPReaderAt_Coalescing(t *testing.T) {
+	var requests int32
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := newRangeServer(t, []byte("0123456789abcdef"), &requests, func() {
+		arrived <- struct{}{}
+		<-release
+	})
+
+	p := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 8}))
+	reader := p.NewHTTPReaderAt(server.URL)
+
+	var wg sync.WaitGroup
+	results := make([]string, 2)
+	read := func(i int) {
+		defer wg.Done()
+		buf := make([]byte, 4)
+		n, err := reader.ReadAt(buf, 2)
+		if err != nil {
+			t.Errorf("ReadAt() error = %v", err)
+		}
+		results[i] = string(buf[:n])
+	}
+
+	wg.Add(2)
+	go read(0)
+	<-arrived
+	go read(1)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if results[0] != "2345" || results[1] != "2345" {
+		t.Errorf("results = %q, want both \"2345\"", results)
+	}
+	stats := reader.Stats()
+	if requests != 1 || stats.Coalesced+stats.CacheHits != 1 {
+		t.Errorf("requests = %d, Stats() = %+v, want one request shared by both reads", requests, stats)
+	}
+}
+
+func TestParseBinaryFromRemoteFile_Stats(t *testing.T) {
+	var requests int32
+	server := newRangeServer(t, readTestBinary(t), &requests, nil)
+
+	info, err := NewParser().ParseBinaryFromRemoteFile(server.URL)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromRemoteFile() error = %v", err)
+	}
+	stats := info.RemoteStats
+	if stats == nil {
+		t.Fatal("RemoteStats = nil")
+	}
+	if stats.Requests != int64(requests) || stats.BytesFetched == 0 || stats.CacheHits == 0 {
+		t.Errorf("RemoteStats = %+v, want %d requests with cache hits", stats, requests)
+	}
+}
+
+func TestHTTPReaderAt_ResumeInterruptedResponse(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		first := len(ranges) == 1
+		mu.Unlock()
+		if first {
+			// 声明完整的长度，只发送一半后断开连接
+			w.Header().Set("Content-Range", "bytes 0-15/16")
+			w.Header().Set("Content-Length", "16")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(data[:8])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	opts := HTTPCacheOptions{BlockSize: 16, Readahead: -1}
+	p := NewParser(WithHTTPCache(opts), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	buf := make([]byte, 16)
+	if n, err := p.NewHTTPReaderAt(server.URL).ReadAt(buf, 0); err != nil || string(buf[:n]) != string(data) {
+		t.Fatalf("ReadAt() = %q, %v, want %q", buf[:n], err, data)
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=8-15" {
+		t.Errorf("Range headers = %q, want the second request to resume at offset 8", ranges)
+	}
+
+	// 不重试时返回包装了尝试次数的错误
+	mu.Lock()
+	ranges = nil
+	mu.Unlock()
+	_, err := NewParser(WithHTTPCache(opts)).NewHTTPReaderAt(server.URL).ReadAt(buf, 0)
+	var rerr *RetryError
+	if !errors.As(err, &rerr) || rerr.Attempts != 1 {
+		t.Errorf("ReadAt() error = %v, want *RetryError after 1 attempt", err)
+	}
+}
@@ -0,0 +1,264 @@
+package gobinaryparser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// probe 发送HEAD请求，记录文件大小、ETag和Last-Modified；服务器声明 Accept-Ranges: none 时不再尝试范围请求，
+// 直接下载整个文件。服务器不支持HEAD请求时忽略探测结果，只有404/410、重试耗尽和下载失败时返回错误。
+func (c *blockCache) probe(h *HTTPReaderAt) error {
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodHead, h.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.parser.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	c.requests.Add(1)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return httpStatusError(resp)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		h.parser.logger.Debug("HEAD请求失败，忽略探测结果", "url", h.url, "status", resp.Status)
+		return nil
+	}
+
+	c.mu.Lock()
+	c.etag = resp.Header.Get("ETag")
+	c.lastModified = resp.Header.Get("Last-Modified")
+	if resp.ContentLength >= 0 {
+		c.total = resp.ContentLength
+	}
+	c.mu.Unlock()
+	if resp.ContentLength >= 0 {
+		c.setSize(resp.ContentLength)
+	}
+	if strings.EqualFold(strings.TrimSpace(resp.Header.Get("Accept-Ranges")), "none") {
+		c.mu.Lock()
+		c.noRanges = true
+		c.mu.Unlock()
+		h.parser.logger.Info("服务器声明不支持范围请求，将下载整个文件", "url", h.url, "size", resp.ContentLength)
+		return c.fetchFull(h)
+	}
+	return nil
+}
+
+// fetchFull 发送不带Range头的GET请求，将整个文件下载到内存或临时文件中
+func (c *blockCache) fetchFull(h *HTTPReaderAt) error {
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.parser.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.requests.Add(1)
+
+	if resp.StatusCode != http.StatusOK {
+		return httpStatusError(resp)
+	}
+	_, err = c.spoolFull(h, resp)
+	return err
+}
+
+// readRange 发送一个范围请求，将从off开始的数据读取到buf中。
+// eof表示读取的数据到达了文件末尾；服务器返回的范围比请求的短但没有到达末尾时，n小于len(buf)且eof为false，
+// 由调用者继续请求剩余的部分。响应体中断时返回已读取的字节数和错误。
+// 服务器忽略Range头返回整个文件时，改为下载整个文件（不超过解析器的下载大小限制）。
+func (c *blockCache) readRange(h *HTTPReaderAt, off int64, buf []byte) (n int, eof bool, err error) {
+	end := off + int64(len(buf)) - 1
+	ifRange := c.ifRange()
+	resp, err := h.get(off, end, ifRange)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+	c.requests.Add(1)
+
+	expected := int64(-1) // 响应体中属于请求范围的字节数，未知时为-1
+	total := int64(-1)    // 服务器报告的文件大小，未知时为-1
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if expected, total, err = c.checkPartial(resp, off, end); err != nil {
+			return 0, false, err
+		}
+	case http.StatusOK:
+		if ifRange != "" {
+			// 服务器之前返回了206，带If-Range的请求返回200说明文件已被替换
+			return 0, false, fmt.Errorf("%w: 服务器对If-Range %s 返回了完整的文件", ErrRemoteChanged, ifRange)
+		}
+		return c.downloadFull(h, resp, off, buf)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 请求的起始位置超出了文件末尾
+		_, _, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || total < 0 {
+			total = off
+		}
+		c.setSize(total)
+		return 0, true, nil
+	default:
+		return 0, false, httpStatusError(resp)
+	}
+
+	want := int64(len(buf))
+	if expected >= 0 && expected < want {
+		want = expected
+	}
+	n, err = io.ReadFull(resp.Body, buf[:want])
+	c.bytesFetched.Add(int64(n))
+	switch {
+	case err == nil:
+		// 服务器可以返回比请求短的范围，只有到达报告的文件大小时才是文件末尾
+		return n, total >= 0 && off+int64(n) >= total, nil
+	case expected < 0 && (err == io.EOF || err == io.ErrUnexpectedEOF):
+		// 长度未知的响应提前结束，视为到达了文件末尾
+		return n, true, nil
+	default:
+		return n, false, fmt.Errorf("%w: %w", errResponseInterrupted, err)
+	}
+}
+
+// checkPartial 验证206响应的Content-Range与请求的范围[start, end]一致，且文件的ETag和大小没有变化，
+// 返回响应中数据的字节数和服务器报告的文件大小（未知时为-1）
+func (c *blockCache) checkPartial(resp *http.Response, start, end int64) (int64, int64, error) {
+	contentRange := resp.Header.Get("Content-Range")
+	first, last, total, ok := parseContentRange(contentRange)
+	if !ok || first < 0 {
+		return 0, 0, fmt.Errorf("服务器返回了无效的Content-Range: %q", contentRange)
+	}
+	if first != start || last > end {
+		return 0, 0, fmt.Errorf("服务器返回的范围 %d-%d 与请求的范围 %d-%d 不符", first, last, start, end)
+	}
+
+	etag := resp.Header.Get("ETag")
+	c.mu.Lock()
+	switch {
+	case c.etag != "" && etag != "" && etag != c.etag:
+		c.mu.Unlock()
+		return 0, 0, fmt.Errorf("%w: ETag从 %s 变为 %s", ErrRemoteChanged, c.etag, etag)
+	case c.total >= 0 && total >= 0 && total != c.total:
+		c.mu.Unlock()
+		return 0, 0, fmt.Errorf("%w: 文件大小从 %d 变为 %d 字节", ErrRemoteChanged, c.total, total)
+	}
+	if c.etag == "" {
+		c.etag = etag
+	}
+	if c.lastModified == "" {
+		c.lastModified = resp.Header.Get("Last-Modified")
+	}
+	if total >= 0 {
+		c.total = total
+	}
+	c.rangesOK = true
+	c.mu.Unlock()
+
+	if total >= 0 {
+		c.setSize(total)
+	}
+	return last - first + 1, total, nil
+}
+
+// ifRange 返回后续范围请求的If-Range头：服务器返回过206响应后使用强ETag，没有时使用Last-Modified
+func (c *blockCache) ifRange() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.rangesOK {
+		return ""
+	}
+	if c.etag != "" && !strings.HasPrefix(c.etag, "W/") {
+		return c.etag
+	}
+	return c.lastModified
+}
+
+// downloadFull 将忽略了Range头的200响应作为整个文件下载到内存或临时文件中，并从中读取off开始的数据
+func (c *blockCache) downloadFull(h *HTTPReaderAt, resp *http.Response, off int64, buf []byte) (int, bool, error) {
+	h.parser.logger.Info("服务器忽略了范围请求，下载整个文件", "url", h.url, "size", resp.ContentLength)
+	spool, err := c.spoolFull(h, resp)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if off >= spool.size {
+		return 0, true, nil
+	}
+	n, err := spool.ReadAt(buf, off)
+	if errors.Is(err, io.EOF) {
+		return n, true, nil
+	}
+	return n, false, err
+}
+
+// spoolFull 将200响应的响应体作为整个文件保存，之后的读取直接使用保存的文件
+func (c *blockCache) spoolFull(h *HTTPReaderAt, resp *http.Response) (*streamSpool, error) {
+	var body io.Reader = resp.Body
+	if h.parser.progress != nil {
+		body = newProgressReader(resp.Body, h.url, resp.ContentLength, h.parser.progress)
+	}
+	spool, err := spoolStream(h.ctx, body, &StreamOptions{
+		MaxSize:  h.parser.maxDownloadSize,
+		SizeHint: resp.ContentLength,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("服务器不支持范围请求，下载整个文件失败: %w", err)
+	}
+	c.bytesFetched.Add(spool.size)
+	c.fullDownload.Store(true)
+
+	c.mu.Lock()
+	if c.full != nil {
+		// 另一个goroutine已经下载了整个文件
+		spool.Close()
+		spool = c.full
+	} else {
+		c.full = spool
+	}
+	c.mu.Unlock()
+	c.setSize(spool.size)
+	return spool, nil
+}
+
+// parseContentRange 解析 "bytes 0-99/1234"、"bytes 0-99/*" 或 "bytes */1234" 形式的Content-Range头，
+// 未知的部分返回-1
+func parseContentRange(header string) (first, last, total int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !found {
+		return 0, 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+
+	total = -1
+	if size = strings.TrimSpace(size); size != "*" {
+		var err error
+		if total, err = strconv.ParseInt(size, 10, 64); err != nil || total < 0 {
+			return 0, 0, 0, false
+		}
+	}
+
+	if rng = strings.TrimSpace(rng); rng == "*" {
+		return -1, -1, total, total >= 0
+	}
+	a, b, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+	first, err1 := strconv.ParseInt(strings.TrimSpace(a), 10, 64)
+	last, err2 := strconv.ParseInt(strings.TrimSpace(b), 10, 64)
+	if err1 != nil || err2 != nil || first < 0 || last < first || (total >= 0 && last >= total) {
+		return 0, 0, 0, false
+	}
+	return first, last, total, true
+}
@@ -0,0 +1,182 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPReaderAt_IgnoredRange(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	reader := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 4})).NewHTTPReaderAt(server.URL)
+	defer reader.Close()
+	buf := make([]byte, 3)
+	for _, off := range []int64{5, 13, 0} {
+		if n, err := reader.ReadAt(buf, off); err != nil || string(buf[:n]) != string(data[off:off+3]) {
+			t.Errorf("ReadAt(%d) = %q, %v, want %q", off, buf[:n], err, data[off:off+3])
+		}
+	}
+	if requests != 1 || !reader.Stats().FullDownload {
+		t.Errorf("requests = %d, Stats() = %+v, want a single full download", requests, reader.Stats())
+	}
+}
+
+func TestParseBinaryFromRemoteFile_IgnoredRange(t *testing.T) {
+	data := readTestBinary(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	info, err := NewParser().ParseBinaryFromRemoteFile(server.URL)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromRemoteFile() error = %v", err)
+	}
+	if info.GoVersion == "" || !info.RemoteStats.FullDownload {
+		t.Errorf("GoVersion = %q, RemoteStats = %+v, want a parsed full download", info.GoVersion, info.RemoteStats)
+	}
+
+	_, err = NewParser(WithMaxDownloadSize(1024)).ParseBinaryFromRemoteFile(server.URL)
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ParseBinaryFromRemoteFile() error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestHTTPReaderAt_InvalidContentRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 无论请求什么范围，都返回文件开头
+		w.Header().Set("Content-Range", "bytes 0-3/16")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("0123"))
+	}))
+	defer server.Close()
+
+	reader := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 4})).NewHTTPReaderAt(server.URL)
+	if _, err := reader.ReadAt(make([]byte, 2), 8); err == nil {
+		t.Error("ReadAt() accepted a response for the wrong range")
+	}
+}
+
+func TestHTTPReaderAt_ShortPartialContent(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 每个响应最多返回4个字节，例如请求0-23时返回 "bytes 0-3/64"
+		var first, last int64
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &first, &last); err == nil {
+			r.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", first, min(last, first+3)))
+		}
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	reader := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 16, Readahead: -1})).NewHTTPReaderAt(server.URL)
+	buf := make([]byte, 10)
+	if n, err := reader.ReadAt(buf, 40); err != nil || string(buf[:n]) != string(data[40:50]) {
+		t.Fatalf("ReadAt(40) = %q, %v, want %q", buf[:n], err, data[40:50])
+	}
+	n, err := reader.ReadAt(buf, 60)
+	if !errors.Is(err, io.EOF) || string(buf[:n]) != string(data[60:]) {
+		t.Errorf("ReadAt(60) = %q, %v, want %q, io.EOF", buf[:n], err, data[60:])
+	}
+}
+
+func TestHTTPReaderAt_RemoteChanged(t *testing.T) {
+	var version atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, etag := "0123456789abcdef", `"v1"`
+		if version.Load() > 0 {
+			content, etag = "fedcba9876543210", `"v2"`
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader([]byte(content)))
+	}))
+	defer server.Close()
+
+	reader := NewParser(WithHTTPCache(HTTPCacheOptions{BlockSize: 4, Readahead: -1})).NewHTTPReaderAt(server.URL)
+	buf := make([]byte, 2)
+	if _, err := reader.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	version.Store(1)
+	if _, err := reader.ReadAt(buf, 8); !errors.Is(err, ErrRemoteChanged) {
+		t.Errorf("ReadAt() after the file changed error = %v, want ErrRemoteChanged", err)
+	}
+}
+
+func TestHTTPReaderAt_HEADProbe(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	reader := NewParser(WithHEADProbe(true)).NewHTTPReaderAt(server.URL)
+	if _, err := reader.ReadAt(make([]byte, 2), 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReadAt() error = %v, want ErrNotFound", err)
+	}
+	if len(methods) != 1 || methods[0] != http.MethodHead {
+		t.Errorf("methods = %v, want a single HEAD request", methods)
+	}
+}
+
+func TestHTTPReaderAt_HEADProbeNoRanges(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Ranges", "none")
+		if r.Method == http.MethodGet {
+			ranges = append(ranges, r.Header.Get("Range"))
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	reader := NewParser(WithHEADProbe(true), WithHTTPCache(HTTPCacheOptions{BlockSize: 4})).NewHTTPReaderAt(server.URL)
+	defer reader.Close()
+	buf := make([]byte, 3)
+	for _, off := range []int64{5, 13} {
+		if n, err := reader.ReadAt(buf, off); err != nil || string(buf[:n]) != string(data[off:off+3]) {
+			t.Errorf("ReadAt(%d) = %q, %v, want %q", off, buf[:n], err, data[off:off+3])
+		}
+	}
+	if len(ranges) != 1 || ranges[0] != "" || !reader.Stats().FullDownload {
+		t.Errorf("Range headers = %q, Stats() = %+v, want a single GET without Range", ranges, reader.Stats())
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header             string
+		first, last, total int64
+		ok                 bool
+	}{
+		{"bytes 0-99/1234", 0, 99, 1234, true},
+		{"bytes 100-199/*", 100, 199, -1, true},
+		{"bytes */1234", -1, -1, 1234, true},
+		{"bytes 0-99/50", 0, 0, 0, false},
+		{"bytes 10-5/100", 0, 0, 0, false},
+		{"items 0-9/10", 0, 0, 0, false},
+		{"", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		first, last, total, ok := parseContentRange(tt.header)
+		if ok != tt.ok || (ok && (first != tt.first || last != tt.last || total != tt.total)) {
+			t.Errorf("parseContentRange(%q) = %d, %d, %d, %t, want %d, %d, %d, %t",
+				tt.header, first, last, total, ok, tt.first, tt.last, tt.total, tt.ok)
+		}
+	}
+}
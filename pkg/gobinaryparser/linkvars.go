@@ -0,0 +1,194 @@
+package gobinaryparser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LinkerVar 表示通过 -ldflags "-X importpath.name=value" 在链接时设置的字符串变量
+// 示例：
+//
+//	{
+//	  "name": "main.version",
+//	  "value": "v1.4.2",
+//	  "found": true,
+//	  "recorded": "v1.4.2",
+//	  "in_ldflags": true
+//	}
+type LinkerVar struct {
+	Name      string `json:"name"`               // 变量的完整名称，例如 "main.version" 或 "github.com/example/myapp/internal/build.Commit"
+	Value     string `json:"value"`              // 从二进制文件数据段中读取的值
+	Found     bool   `json:"found"`              // 是否在符号表中找到了该变量并读取了它的值
+	Recorded  string `json:"recorded,omitempty"` // -ldflags 构建设置中为该变量记录的值
+	InLDFlags bool   `json:"in_ldflags"`         // 构建设置的 -ldflags 中是否有设置该变量的 -X 参数
+	Error     string `json:"error,omitempty"`    // 读取失败的原因，例如符号表已被剥离
+}
+
+// ReadLinkerVars 读取Go二进制文件中通过 -ldflags -X 设置的字符串变量的值。
+// 没有指定变量名时，读取构建设置的 -ldflags 中所有 -X 参数设置的变量；
+// 使用 -trimpath 构建时go命令不记录 -ldflags，此时需要指定变量名。
+// 变量的值从符号表中变量对应的Go字符串头读取，因此要求二进制文件保留了符号表（没有使用 -ldflags=-s）。
+//
+// 参数:
+//   - filePath: Go二进制文件的路径
+//   - names: 要读取的变量名，例如 "main.version"，为空时使用 -ldflags 中的 -X 参数
+//
+// 返回:
+//   - []LinkerVar: 每个变量的读取结果，找不到的变量Found为false并在Error中说明原因
+//   - error: 如果文件无法解析，则返回错误信息
+//
+// 使用示例:
+//
+//	vars, err := gobinaryparser.ReadLinkerVars("/usr/local/bin/myapp", "main.version", "main.commit")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, v := range vars {
+//		fmt.Printf("%s = %q\n", v.Name, v.Value)
+//	}
+func ReadLinkerVars(filePath string, names ...string) ([]LinkerVar, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开二进制文件失败: %w", err)
+	}
+	defer f.Close()
+	return ReadLinkerVarsFromReader(f, names...)
+}
+
+// ReadLinkerVarsFromReader 从io.ReaderAt接口读取Go二进制文件中通过 -ldflags -X 设置的字符串变量的值，
+// 参见 ReadLinkerVars
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//   - names: 要读取的变量名，为空时使用 -ldflags 中的 -X 参数
+//
+// 返回:
+//   - []LinkerVar: 每个变量的读取结果
+//   - error: 如果数据无法解析，则返回错误信息
+//
+// 使用示例:
+//
+//	data, _ := os.ReadFile("/usr/local/bin/myapp")
+//	vars, err := gobinaryparser.ReadLinkerVarsFromReader(bytes.NewReader(data))
+func ReadLinkerVarsFromReader(r io.ReaderAt, names ...string) ([]LinkerVar, error) {
+	var recorded map[string]string
+	var flagNames []string
+	if info, err := ParseBinaryFromReader(r); err == nil && info.Degraded == nil {
+		config := info.BuildConfig
+		if config == nil {
+			config = NewBuildConfig(info.BuildSettings)
+		}
+		flagNames, recorded = linkerXFlags(config.LDFlags)
+	}
+	if len(names) == 0 {
+		names = flagNames
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+	return exe.linkerVars(names, recorded), nil
+}
+
+// linkerXFlags 从拆分后的链接器参数中提取 -X 参数，返回按出现顺序排列的变量名和变量值。
+// 同一变量出现多次时以最后一次为准，与链接器的行为一致。
+func linkerXFlags(ldflags []string) (names []string, values map[string]string) {
+	values = make(map[string]string)
+	for i := 0; i < len(ldflags); i++ {
+		arg := ldflags[i]
+		var def string
+		switch {
+		case arg == "-X" || arg == "--X":
+			if i+1 >= len(ldflags) {
+				continue
+			}
+			i++
+			def = ldflags[i]
+		case strings.HasPrefix(arg, "-X="):
+			def = arg[len("-X="):]
+		case strings.HasPrefix(arg, "--X="):
+			def = arg[len("--X="):]
+		default:
+			continue
+		}
+
+		name, value, ok := strings.Cut(def, "=")
+		if !ok || name == "" {
+			continue
+		}
+		if _, seen := values[name]; !seen {
+			names = append(names, name)
+		}
+		values[name] = value
+	}
+	return names, values
+}
+
+// linkerVars 在符号表中查找变量并读取它们的值，recorded为 -ldflags 中记录的值
+func (e *executable) linkerVars(names []string, recorded map[string]string) []LinkerVar {
+	syms, symErr := e.symbols()
+	byName := make(map[string]*exeSymbol, len(syms))
+	for i := range syms {
+		byName[syms[i].Name] = &syms[i]
+	}
+
+	vars := make([]LinkerVar, 0, len(names))
+	for _, name := range names {
+		v := LinkerVar{Name: name}
+		v.Recorded, v.InLDFlags = recorded[name]
+
+		sym := byName[name]
+		if sym == nil {
+			// 链接器对导入路径最后一个元素中的"."等字符进行了转义
+			sym = byName[symbolPrefix(name)]
+		}
+		switch {
+		case symErr != nil:
+			v.Error = symErr.Error()
+		case sym == nil:
+			v.Error = fmt.Sprintf("未找到符号 %s", name)
+		case sym.Kind == symBSS:
+			// 未初始化的字符串变量位于BSS段，值为空字符串
+			v.Found = true
+		default:
+			value, _, err := e.readGoStringAt(sym.Addr)
+			if err != nil {
+				v.Error = fmt.Sprintf("读取 %s 的值失败: %v", name, err)
+				break
+			}
+			v.Found = true
+			v.Value = value
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// symbolPrefix 按链接器的规则转义符号名中的导入路径：导入路径最后一个元素中的"."、
+// 以及整个路径中的控制字符、空格、"%"和"\""被替换为 %xx 形式。
+// 例如 "gopkg.in/yaml.v3.Version" 的符号名为 "gopkg.in/yaml%2ev3.Version"。
+func symbolPrefix(name string) string {
+	dot := strings.LastIndexByte(name, '.')
+	if dot < 0 {
+		return name
+	}
+	path, sym := name[:dot], name[dot:]
+	lastSlash := strings.LastIndexByte(path, '/')
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c <= ' ' || c == '%' || c == '"' || c >= 0x7f || (c == '.' && i > lastSlash) {
+			fmt.Fprintf(&b, "%%%02x", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String() + sym
+}
@@ -0,0 +1,134 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+const linkerVarsSrc = `package main
+
+import "fmt"
+
+var (
+	version string
+	commit  = "none"
+	unset   string
+)
+
+func main() { fmt.Println(version, commit, unset) }
+`
+
+func TestReadLinkerVars(t *testing.T) {
+	tests := []struct {
+		name      string
+		buildArgs []string
+	}{
+		{"exe", nil},
+		{"pie", []string{"-buildmode=pie"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "pie" && runtime.GOOS != "linux" {
+				t.Skip("PIE test only runs on linux")
+			}
+			args := append([]string{"-ldflags=-X main.version=v1.2.3 -X 'main.commit=abc def'"}, tt.buildArgs...)
+			path := buildTestProgram(t, linkerVarsSrc, []string{"CGO_ENABLED=0"}, args...)
+
+			vars, err := ReadLinkerVars(path)
+			if err != nil {
+				t.Fatalf("ReadLinkerVars() error = %v", err)
+			}
+			want := []LinkerVar{
+				{Name: "main.version", Value: "v1.2.3", Found: true, Recorded: "v1.2.3", InLDFlags: true},
+				{Name: "main.commit", Value: "abc def", Found: true, Recorded: "abc def", InLDFlags: true},
+			}
+			if !reflect.DeepEqual(vars, want) {
+				t.Errorf("ReadLinkerVars() = %+v, want %+v", vars, want)
+			}
+
+			vars, err = ReadLinkerVars(path, "main.unset", "main.missing")
+			if err != nil {
+				t.Fatalf("ReadLinkerVars() error = %v", err)
+			}
+			if len(vars) != 2 || !vars[0].Found || vars[0].Value != "" || vars[1].Found || vars[1].Error == "" {
+				t.Errorf("ReadLinkerVars(unset, missing) = %+v", vars)
+			}
+		})
+	}
+}
+
+// 部分链接器（例如lld）不把相对重定位的值写入文件，数据段中的指针为0，只能从重定位表中读取
+func TestReadLinkerVars_ZeroedRelocatedPointer(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("PIE test only runs on linux")
+	}
+	path := buildTestProgram(t, linkerVarsSrc, []string{"CGO_ENABLED=0"}, "-buildmode=pie", "-ldflags=-X main.version=v1.2.3")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read binary: %v", err)
+	}
+
+	exe, err := openExecutable(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("openExecutable() error = %v", err)
+	}
+	sym, err := exe.lookupSymbol("main.version")
+	if err != nil {
+		t.Fatalf("lookupSymbol() error = %v", err)
+	}
+	if _, ok := exe.elfRelativeRelocs()[sym.Addr]; !ok {
+		t.Skip("No relative relocation for main.version")
+	}
+	offset, _, ok := exe.fileOffset(sym.Addr)
+	if !ok {
+		t.Fatal("Symbol is not mapped to the file")
+	}
+	copy(data[offset:], make([]byte, exe.ptrSize()))
+
+	vars, err := ReadLinkerVarsFromReader(bytes.NewReader(data), "main.version")
+	if err != nil {
+		t.Fatalf("ReadLinkerVarsFromReader() error = %v", err)
+	}
+	if len(vars) != 1 || vars[0].Value != "v1.2.3" {
+		t.Errorf("ReadLinkerVarsFromReader() = %+v, want value v1.2.3", vars)
+	}
+}
+
+func TestReadLinkerVars_Stripped(t *testing.T) {
+	path := buildTestProgram(t, linkerVarsSrc, []string{"CGO_ENABLED=0"}, "-ldflags=-s -X main.version=v1.2.3")
+
+	vars, err := ReadLinkerVars(path)
+	if err != nil {
+		t.Fatalf("ReadLinkerVars() error = %v", err)
+	}
+	if len(vars) != 1 || vars[0].Found || vars[0].Error == "" || vars[0].Recorded != "v1.2.3" {
+		t.Errorf("ReadLinkerVars() = %+v, want an error for the stripped symbol table", vars)
+	}
+}
+
+func TestLinkerXFlags(t *testing.T) {
+	names, values := linkerXFlags([]string{"-s", "-X", "main.version=v1", "-X=main.commit=a=b", "--X", "main.version=v2", "-w", "-X"})
+	if want := []string{"main.version", "main.commit"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	if want := map[string]string{"main.version": "v2", "main.commit": "a=b"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+}
+
+func TestSymbolPrefix(t *testing.T) {
+	tests := map[string]string{
+		"main.version":                     "main.version",
+		"gopkg.in/yaml.v3.Version":         "gopkg.in/yaml%2ev3.Version",
+		"github.com/example/app/build.Tag": "github.com/example/app/build.Tag",
+		"example.com/a b.X":                "example.com/a%20b.X",
+	}
+	for name, want := range tests {
+		if got := symbolPrefix(name); got != want {
+			t.Errorf("symbolPrefix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package gobinaryparser
 
 import (
 	"bytes"
-	"debug/buildinfo"
 	"fmt"
 	"io"
 	"os"
@@ -16,7 +15,7 @@ import (
 //
 // 返回:
 //   - *BinaryInfo: 包含二进制文件依赖信息的结构体
-//   - error: 如果文件不存在或解析过程中发生错误，则返回错误信息
+//   - error: 如果文件不存在或解析过程中发生错误，则返回 *ParseError；文件不存在时包装 ErrNotFound
 //
 // 使用示例:
 //
@@ -29,12 +28,17 @@ import (
 //		fmt.Printf("%d. %s@%s\n", i+1, dep.Path, dep.Version)
 //	}
 func ParseBinaryFromPath(path string) (*BinaryInfo, error) {
+	return defaultParser.ParseBinaryFromPath(path)
+}
+
+// ParseBinaryFromPath 验证文件存在后解析指定路径的Go二进制文件，参见 ParseBinaryFromPath
+func (p *Parser) ParseBinaryFromPath(path string) (*BinaryInfo, error) {
 	// 检查文件是否存在
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, fmt.Errorf("二进制文件不存在: %s", path)
+		return nil, newParseError(path, "file", fmt.Errorf("%w: %s", ErrNotFound, path))
 	}
 
-	return ParseBinary(path)
+	return p.ParseBinary(path)
 }
 
 // ParseBinaryFromFile 是 ParseBinaryFromPath 的别名函数，用于兼容旧代码。
@@ -54,7 +58,12 @@ func ParseBinaryFromPath(path string) (*BinaryInfo, error) {
 //		log.Fatalf("解析二进制文件失败: %v", err)
 //	}
 func ParseBinaryFromFile(path string) (*BinaryInfo, error) {
-	return ParseBinaryFromPath(path)
+	return defaultParser.ParseBinaryFromPath(path)
+}
+
+// ParseBinaryFromFile 是 Parser.ParseBinaryFromPath 的别名方法
+func (p *Parser) ParseBinaryFromFile(path string) (*BinaryInfo, error) {
+	return p.ParseBinaryFromPath(path)
 }
 
 // ParseBinaryFromBytes 从字节切片解析Go二进制文件。
@@ -77,13 +86,18 @@ func ParseBinaryFromFile(path string) (*BinaryInfo, error) {
 //	}
 //	fmt.Printf("Go版本: %s\n", info.GoVersion)
 func ParseBinaryFromBytes(data []byte) (*BinaryInfo, error) {
+	return defaultParser.ParseBinaryFromBytes(data)
+}
+
+// ParseBinaryFromBytes 从字节切片解析Go二进制文件，结果不会被缓存，参见 ParseBinaryFromBytes
+func (p *Parser) ParseBinaryFromBytes(data []byte) (*BinaryInfo, error) {
 	reader := bytes.NewReader(data)
-	info, err := buildinfo.Read(reader)
+	result, err := p.readBinaryInfo(reader, "", "bytes")
 	if err != nil {
-		return nil, fmt.Errorf("从字节读取构建信息失败: %w", err)
+		return nil, newParseError("", "bytes", err)
 	}
-
-	return createBinaryInfo(info, "", "bytes")
+	p.store("", result)
+	return result, nil
 }
 
 // ParseBinaryFromReader 从io.ReaderAt接口解析Go二进制文件。
@@ -112,10 +126,15 @@ func ParseBinaryFromBytes(data []byte) (*BinaryInfo, error) {
 //	reader := bytes.NewReader(data)
 //	info, err := gobinaryparser.ParseBinaryFromReader(reader)
 func ParseBinaryFromReader(r io.ReaderAt) (*BinaryInfo, error) {
-	info, err := buildinfo.Read(r)
+	return defaultParser.ParseBinaryFromReader(r)
+}
+
+// ParseBinaryFromReader 从io.ReaderAt接口解析Go二进制文件，结果不会被缓存，参见 ParseBinaryFromReader
+func (p *Parser) ParseBinaryFromReader(r io.ReaderAt) (*BinaryInfo, error) {
+	result, err := p.readBinaryInfo(r, "", "reader")
 	if err != nil {
-		return nil, fmt.Errorf("从读取器读取构建信息失败: %w", err)
+		return nil, newParseError("", "reader", err)
 	}
-
-	return createBinaryInfo(info, "", "reader")
+	p.store("", result)
+	return result, nil
 }
@@ -0,0 +1,510 @@
+package gobinaryparser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VersionKind 表示模块版本的类型
+type VersionKind string
+
+const (
+	VersionRelease      VersionKind = "release"      // 正式版本，例如 v1.2.3
+	VersionPrerelease   VersionKind = "prerelease"   // 预发布版本，例如 v1.2.3-rc.1
+	VersionPseudo       VersionKind = "pseudo"       // 伪版本，例如 v0.0.0-20230101120000-abcdef123456
+	VersionIncompatible VersionKind = "incompatible" // 没有go.mod的v2及以上版本，例如 v2.0.0+incompatible
+	VersionDevel        VersionKind = "devel"        // 从工作区构建的主模块，版本为 (devel)
+	VersionLocal        VersionKind = "local"        // 被替换为本地目录，版本为 (devel)
+	VersionInvalid      VersionKind = "invalid"      // 不是有效的语义化版本
+)
+
+// develVersion 是从工作区构建的主模块的版本
+const develVersion = "(devel)"
+
+// pseudoVersionPattern 匹配Go模块伪版本的三种形式：
+// vX.0.0-yyyymmddhhmmss-abcdefabcdef、vX.Y.Z-pre.0.yyyymmddhhmmss-abcdefabcdef 和 vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdefabcdef
+var pseudoVersionPattern = regexp.MustCompile(`^v[0-9]+\.(0\.0-|[0-9]+\.[0-9]+-([^+]*\.)?0\.)([0-9]{14})-([A-Za-z0-9]+)(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// ClassifyVersion 返回模块版本的类型。
+// 伪版本和预发布版本即使带有 +incompatible 后缀也分别归为 VersionPseudo 和 VersionPrerelease。
+//
+// 参数:
+//   - version: 模块版本，例如 DependencyInfo.Version
+//
+// 返回:
+//   - VersionKind: 版本类型，无法识别（包括空字符串）时返回 VersionInvalid
+//
+// 使用示例:
+//
+//	fmt.Println(gobinaryparser.ClassifyVersion("v0.0.0-20230101120000-abcdef123456")) // pseudo
+//	fmt.Println(gobinaryparser.ClassifyVersion("v2.3.0+incompatible"))                // incompatible
+func ClassifyVersion(version string) VersionKind {
+	switch {
+	case version == develVersion:
+		return VersionDevel
+	case !IsValidVersion(version):
+		return VersionInvalid
+	case IsPseudoVersion(version):
+		return VersionPseudo
+	}
+	sv, _ := parseSemver(version)
+	switch {
+	case sv.prerelease != "":
+		return VersionPrerelease
+	case sv.build == "+incompatible":
+		return VersionIncompatible
+	}
+	return VersionRelease
+}
+
+// VersionKind 返回依赖实际使用的版本的类型：被替换为本地目录时返回 VersionLocal，
+// 被替换为其他模块时返回替换版本的类型
+//
+// 返回:
+//   - VersionKind: 版本类型
+func (dep DependencyInfo) VersionKind() VersionKind {
+	// 替换为本地目录时构建信息中的版本为 (devel)，不是主模块的开发版本
+	if dep.ReplaceKind() == ReplaceLocal {
+		return VersionLocal
+	}
+	return ClassifyVersion(dep.builtVersion())
+}
+
+// builtVersion 返回依赖实际编译进二进制文件的版本，被替换时为替换模块的版本
+func (dep DependencyInfo) builtVersion() string {
+	if dep.Replace != nil {
+		return dep.Replace.Version
+	}
+	return dep.Version
+}
+
+// IsPseudoVersion 判断版本是否为伪版本（引用某个提交而不是标签的版本）
+func IsPseudoVersion(version string) bool {
+	return strings.Count(version, "-") >= 2 && IsValidVersion(version) && pseudoVersionPattern.MatchString(version)
+}
+
+// PseudoVersionCommit 返回伪版本中的提交哈希前缀（通常为12个字符）
+//
+// 参数:
+//   - version: 伪版本
+//
+// 返回:
+//   - string: 提交哈希前缀
+//   - error: 如果不是伪版本，则返回错误信息
+//
+// 使用示例:
+//
+//	commit, _ := gobinaryparser.PseudoVersionCommit("v0.0.0-20230101120000-abcdef123456")
+//	fmt.Println(commit) // abcdef123456
+func PseudoVersionCommit(version string) (string, error) {
+	m := pseudoVersionPattern.FindStringSubmatch(version)
+	if m == nil || !IsValidVersion(version) {
+		return "", fmt.Errorf("不是伪版本: %q", version)
+	}
+	return m[4], nil
+}
+
+// PseudoVersionTime 返回伪版本中记录的提交时间（UTC）
+//
+// 参数:
+//   - version: 伪版本
+//
+// 返回:
+//   - time.Time: 提交时间
+//   - error: 如果不是伪版本或时间戳无效，则返回错误信息
+//
+// 使用示例:
+//
+//	t, _ := gobinaryparser.PseudoVersionTime("v0.0.0-20230101120000-abcdef123456")
+//	fmt.Println(t) // 2023-01-01 12:00:00 +0000 UTC
+func PseudoVersionTime(version string) (time.Time, error) {
+	m := pseudoVersionPattern.FindStringSubmatch(version)
+	if m == nil || !IsValidVersion(version) {
+		return time.Time{}, fmt.Errorf("不是伪版本: %q", version)
+	}
+	t, err := time.Parse("20060102150405", m[3])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("伪版本时间戳无效: %q", version)
+	}
+	return t, nil
+}
+
+// IsValidVersion 判断版本是否为Go模块使用的语义化版本（以v开头，允许 v1、v1.2 简写）
+func IsValidVersion(version string) bool {
+	_, ok := parseSemver(version)
+	return ok
+}
+
+// CompareVersions 按照语义化版本规则比较两个模块版本，a小于、等于、大于b时分别返回-1、0、1。
+// 与Go命令的规则一致：构建元数据（例如 +incompatible）不参与比较，无效版本低于所有有效版本，
+// 两个无效版本相等。
+//
+// 参数:
+//   - a: 第一个版本
+//   - b: 第二个版本
+//
+// 返回:
+//   - int: 比较结果
+//
+// 使用示例:
+//
+//	gobinaryparser.CompareVersions("v1.2.3", "v1.10.0")     // -1
+//	gobinaryparser.CompareVersions("v1.2.3-rc.1", "v1.2.3") // -1
+func CompareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	pa, okA := parseSemver(a)
+	pb, okB := parseSemver(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	if c := compareNumeric(pa.major, pb.major); c != 0 {
+		return c
+	}
+	if c := compareNumeric(pa.minor, pb.minor); c != 0 {
+		return c
+	}
+	if c := compareNumeric(pa.patch, pb.patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(pa.prerelease, pb.prerelease)
+}
+
+// SortVersions 按语义化版本从低到高排序
+//
+// 参数:
+//   - versions: 要排序的版本列表，原地排序
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+}
+
+// SortDependencies 按路径排序依赖，路径相同时按实际使用的版本从低到高排序
+//
+// 参数:
+//   - dependencies: 要排序的依赖列表，原地排序
+//
+// 使用示例:
+//
+//	deps := append([]gobinaryparser.DependencyInfo(nil), info.Dependencies...)
+//	gobinaryparser.SortDependencies(deps)
+func SortDependencies(dependencies []DependencyInfo) {
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		if dependencies[i].Path != dependencies[j].Path {
+			return dependencies[i].Path < dependencies[j].Path
+		}
+		return CompareVersions(dependencies[i].builtVersion(), dependencies[j].builtVersion()) < 0
+	})
+}
+
+// VersionRange 表示模块版本的范围，由逗号分隔的多个比较条件组成，所有条件都满足时版本在范围内，
+// 例如 ">=v0.17.0" 或 ">=v1.2.0, <v1.5.0"
+type VersionRange struct {
+	raw   string
+	terms []versionTerm
+}
+
+// versionTerm 是版本范围中的一个比较条件
+type versionTerm struct {
+	op      string
+	version string
+}
+
+// ParseVersionRange 解析模块版本范围。
+// 每个条件由运算符（<、<=、>、>=、=、==、!=，省略时为=）和版本组成，版本可以省略 "v" 前缀。
+//
+// 参数:
+//   - s: 范围字符串
+//
+// 返回:
+//   - *VersionRange: 解析后的范围
+//   - error: 如果范围为空或包含无效版本，则返回错误信息
+//
+// 使用示例:
+//
+//	r, err := gobinaryparser.ParseVersionRange("<v0.17.0")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	vulnerable := info.FilterVersionRange(r)
+func ParseVersionRange(s string) (*VersionRange, error) {
+	r := &VersionRange{raw: s}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		term := versionTerm{}
+		term.op, part = splitComparison(part)
+		if !strings.HasPrefix(part, "v") {
+			part = "v" + part
+		}
+		if !IsValidVersion(part) {
+			return nil, fmt.Errorf("无效的版本范围 %q: 无效的版本 %q", s, part)
+		}
+		term.version = part
+		r.terms = append(r.terms, term)
+	}
+	if len(r.terms) == 0 {
+		return nil, fmt.Errorf("版本范围为空")
+	}
+	return r, nil
+}
+
+// Contains 判断版本是否在范围内，无效版本（包括本地替换的空版本和 (devel)）不在任何范围内
+//
+// 参数:
+//   - version: 要检查的版本
+//
+// 返回:
+//   - bool: 满足所有条件时返回true
+func (r *VersionRange) Contains(version string) bool {
+	if !IsValidVersion(version) {
+		return false
+	}
+	for _, term := range r.terms {
+		if !compareOp(term.op, CompareVersions(version, term.version)) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回范围的原始字符串
+func (r *VersionRange) String() string {
+	return r.raw
+}
+
+// FilterVersionRange 返回实际使用的版本在范围内的依赖，被替换的依赖按替换模块的版本判断。
+//
+// 参数:
+//   - r: 版本范围
+//
+// 返回:
+//   - []DependencyInfo: 过滤后的依赖列表
+//
+// 使用示例:
+//
+//	r, _ := gobinaryparser.ParseVersionRange("<v0.17.0")
+//	for _, dep := range info.FilterVersionRange(r) {
+//		if dep.Path == "golang.org/x/net" {
+//			fmt.Printf("golang.org/x/net %s 存在已知漏洞\n", dep.Version)
+//		}
+//	}
+func (info *BinaryInfo) FilterVersionRange(r *VersionRange) []DependencyInfo {
+	return info.FilterDependencies(func(dep DependencyInfo) bool {
+		return r.Contains(dep.builtVersion())
+	})
+}
+
+// FilterVersionKind 返回实际使用的版本属于给定类型之一的依赖，例如找出所有伪版本依赖。
+//
+// 参数:
+//   - kinds: 要保留的版本类型
+//
+// 返回:
+//   - []DependencyInfo: 过滤后的依赖列表
+//
+// 使用示例:
+//
+//	pseudo := info.FilterVersionKind(gobinaryparser.VersionPseudo, gobinaryparser.VersionLocal)
+//	fmt.Printf("%d个依赖没有使用正式版本\n", len(pseudo))
+func (info *BinaryInfo) FilterVersionKind(kinds ...VersionKind) []DependencyInfo {
+	return info.FilterDependencies(func(dep DependencyInfo) bool {
+		kind := dep.VersionKind()
+		for _, k := range kinds {
+			if kind == k {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// compareOp 根据比较运算符判断比较结果是否满足条件
+func compareOp(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// semver 是解析后的语义化版本，数字部分保留为字符串以支持任意长度
+type semver struct {
+	major, minor, patch string
+	prerelease          string
+	build               string
+}
+
+// parseSemver 解析以v开头的语义化版本，v1 和 v1.2 分别等价于 v1.0.0 和 v1.2.0，简写形式不能带预发布或构建元数据
+func parseSemver(v string) (semver, bool) {
+	var p semver
+	if !strings.HasPrefix(v, "v") {
+		return p, false
+	}
+	v = v[1:]
+
+	var ok bool
+	if p.major, v, ok = parseNumber(v); !ok {
+		return p, false
+	}
+	if v == "" {
+		p.minor, p.patch = "0", "0"
+		return p, true
+	}
+	if v[0] != '.' {
+		return p, false
+	}
+	if p.minor, v, ok = parseNumber(v[1:]); !ok {
+		return p, false
+	}
+	if v == "" {
+		p.patch = "0"
+		return p, true
+	}
+	if v[0] != '.' {
+		return p, false
+	}
+	if p.patch, v, ok = parseNumber(v[1:]); !ok {
+		return p, false
+	}
+
+	if strings.HasPrefix(v, "-") {
+		end := strings.IndexByte(v, '+')
+		if end < 0 {
+			end = len(v)
+		}
+		p.prerelease, v = v[:end], v[end:]
+		if !validIdentifiers(p.prerelease[1:], true) {
+			return p, false
+		}
+	}
+	if strings.HasPrefix(v, "+") {
+		p.build, v = v, ""
+		if !validIdentifiers(p.build[1:], false) {
+			return p, false
+		}
+	}
+	return p, v == ""
+}
+
+// parseNumber 解析没有前导零的十进制数字
+func parseNumber(v string) (num, rest string, ok bool) {
+	i := 0
+	for i < len(v) && v[i] >= '0' && v[i] <= '9' {
+		i++
+	}
+	if i == 0 || (v[0] == '0' && i > 1) {
+		return "", v, false
+	}
+	return v[:i], v[i:], true
+}
+
+// validIdentifiers 判断以点分隔的标识符列表是否有效，预发布版本中的纯数字标识符不能有前导零
+func validIdentifiers(s string, prerelease bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		numeric := true
+		for i := 0; i < len(id); i++ {
+			c := id[i]
+			switch {
+			case c >= '0' && c <= '9':
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-':
+				numeric = false
+			default:
+				return false
+			}
+		}
+		if prerelease && numeric && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNumeric 比较两个没有前导零的十进制数字字符串
+func compareNumeric(a, b string) int {
+	switch {
+	case len(a) != len(b):
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease 按语义化版本规则比较预发布标识（包含开头的 "-"），没有预发布标识的版本更高
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	ia, ib := strings.Split(a[1:], "."), strings.Split(b[1:], ".")
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if ia[i] == ib[i] {
+			continue
+		}
+		na, nb := isNumeric(ia[i]), isNumeric(ib[i])
+		switch {
+		case na && nb:
+			return compareNumeric(ia[i], ib[i])
+		case na:
+			return -1
+		case nb:
+			return 1
+		case ia[i] < ib[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(ia) < len(ib):
+		return -1
+	case len(ia) > len(ib):
+		return 1
+	}
+	return 0
+}
+
+// isNumeric 判断标识符是否只包含数字
+func isNumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,181 @@
+package gobinaryparser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClassifyVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    VersionKind
+	}{
+		{"v1.2.3", VersionRelease},
+		{"v1.2.3-rc.1", VersionPrerelease},
+		{"v0.0.0-20230101120000-abcdef123456", VersionPseudo},
+		{"v1.2.4-0.20230101120000-abcdef123456", VersionPseudo},
+		{"v1.2.3-pre.0.20230101120000-abcdef123456", VersionPseudo},
+		{"v2.0.0-20190101000000-abcdef123456+incompatible", VersionPseudo},
+		{"v2.3.0+incompatible", VersionIncompatible},
+		{"v2.3.0-beta+incompatible", VersionPrerelease},
+		{"(devel)", VersionDevel},
+		{"", VersionInvalid},
+		{"1.2.3", VersionInvalid},
+		{"v1.02.3", VersionInvalid},
+		{"v1.2.3-01", VersionInvalid},
+	}
+	for _, tt := range tests {
+		if got := ClassifyVersion(tt.version); got != tt.want {
+			t.Errorf("ClassifyVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestDependencyInfo_VersionKind(t *testing.T) {
+	tests := []struct {
+		dep  DependencyInfo
+		want VersionKind
+	}{
+		{DependencyInfo{Path: "a", Version: "v1.0.0"}, VersionRelease},
+		{DependencyInfo{Path: "a", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../a", Version: "(devel)"}}, VersionLocal},
+		{DependencyInfo{Path: "a", Version: "v1.0.0", Replace: &DependencyInfo{Path: "/src/a"}}, VersionLocal},
+		{DependencyInfo{Path: "a", Version: "(devel)"}, VersionDevel},
+		{DependencyInfo{Path: "a", Version: "v1.0.0", Replace: &DependencyInfo{Path: "b", Version: "v0.0.0-20230101120000-abcdef123456"}}, VersionPseudo},
+	}
+	for _, tt := range tests {
+		if got := tt.dep.VersionKind(); got != tt.want {
+			t.Errorf("VersionKind(%+v) = %q, want %q", tt.dep, got, tt.want)
+		}
+	}
+}
+
+func TestPseudoVersion(t *testing.T) {
+	version := "v1.2.4-0.20230101120304-abcdef123456"
+	commit, err := PseudoVersionCommit(version)
+	if err != nil || commit != "abcdef123456" {
+		t.Errorf("PseudoVersionCommit() = %q, %v", commit, err)
+	}
+	tm, err := PseudoVersionTime(version)
+	if err != nil || !tm.Equal(time.Date(2023, 1, 1, 12, 3, 4, 0, time.UTC)) {
+		t.Errorf("PseudoVersionTime() = %v, %v", tm, err)
+	}
+
+	if _, err := PseudoVersionCommit("v1.2.3"); err == nil {
+		t.Error("Expected error for a release version")
+	}
+	if _, err := PseudoVersionTime("v0.0.0-20231399000000-abcdef123456"); err == nil {
+		t.Error("Expected error for an invalid timestamp")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.10.0", -1},
+		{"v1.2.3-rc.1", "v1.2.3", -1},
+		{"v1.2.3-alpha", "v1.2.3-alpha.1", -1},
+		{"v1.2.3-alpha.1", "v1.2.3-alpha.beta", -1},
+		{"v1.2.3-beta.2", "v1.2.3-beta.11", -1},
+		{"v1.2.3-rc.1", "v1.2.3-rc.1", 0},
+		{"v2.0.0+incompatible", "v2.0.0", 0},
+		{"v1", "v1.0.0", 0},
+		{"v1.2", "v1.2.0", 0},
+		{"v0.0.0-20230101120000-abcdef123456", "v0.1.0", -1},
+		{"v1.2.4-0.20230101120000-abcdef123456", "v1.2.3", 1},
+		{"v1.2.4-0.20230101120000-abcdef123456", "v1.2.4", -1},
+		{"v10.0.0", "v9.99.99", 1},
+		{"invalid", "v0.0.1", -1},
+		{"invalid", "(devel)", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := CompareVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSortVersionsAndDependencies(t *testing.T) {
+	versions := []string{"v1.10.0", "v1.2.0", "v1.2.0-rc.1", "(devel)", "v0.0.0-20230101120000-abcdef123456"}
+	SortVersions(versions)
+	want := []string{"(devel)", "v0.0.0-20230101120000-abcdef123456", "v1.2.0-rc.1", "v1.2.0", "v1.10.0"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("SortVersions() = %v, want %v", versions, want)
+	}
+
+	deps := []DependencyInfo{
+		{Path: "b", Version: "v1.0.0"},
+		{Path: "a", Version: "v1.10.0"},
+		{Path: "a", Version: "v1.9.0", Replace: &DependencyInfo{Path: "c", Version: "v1.11.0"}},
+		{Path: "a", Version: "v1.2.0"},
+	}
+	SortDependencies(deps)
+	var got []string
+	for _, dep := range deps {
+		got = append(got, dep.Path+"@"+dep.builtVersion())
+	}
+	if want := []string{"a@v1.2.0", "a@v1.10.0", "a@v1.11.0", "b@v1.0.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortDependencies() = %v, want %v", got, want)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	tests := []struct {
+		r       string
+		version string
+		want    bool
+	}{
+		{"<v0.17.0", "v0.16.9", true},
+		{"<v0.17.0", "v0.17.0", false},
+		{"<0.17.0", "v0.17.0-rc.1", true},
+		{">=v1.2.0, <v1.5.0", "v1.4.9", true},
+		{">=v1.2.0, <v1.5.0", "v1.5.0", false},
+		{"=v2.0.0", "v2.0.0+incompatible", true},
+		{"!=v1.0.0", "v1.0.1", true},
+		{">=v0.0.0", "(devel)", false},
+		{">=v0.0.0", "", false},
+	}
+	for _, tt := range tests {
+		r, err := ParseVersionRange(tt.r)
+		if err != nil {
+			t.Errorf("ParseVersionRange(%q) error = %v", tt.r, err)
+			continue
+		}
+		if got := r.Contains(tt.version); got != tt.want {
+			t.Errorf("%q.Contains(%q) = %v, want %v", tt.r, tt.version, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "<", ">=1.x"} {
+		if _, err := ParseVersionRange(in); err == nil {
+			t.Errorf("ParseVersionRange(%q) expected error", in)
+		}
+	}
+}
+
+func TestBinaryInfo_FilterVersions(t *testing.T) {
+	info := &BinaryInfo{
+		Dependencies: []DependencyInfo{
+			{Path: "golang.org/x/net", Version: "v0.15.0"},
+			{Path: "golang.org/x/text", Version: "v0.20.0"},
+			{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "./b", Version: "(devel)"}},
+			{Path: "github.com/c/d", Version: "v0.0.0-20230101120000-abcdef123456"},
+		},
+	}
+
+	r, _ := ParseVersionRange("<v0.17.0")
+	got := info.FilterVersionRange(r)
+	if len(got) != 2 || got[0].Path != "golang.org/x/net" || got[1].Path != "github.com/c/d" {
+		t.Errorf("FilterVersionRange() = %+v", got)
+	}
+
+	got = info.FilterVersionKind(VersionPseudo, VersionLocal)
+	if len(got) != 2 || got[0].Path != "github.com/a/b" || got[1].Path != "github.com/c/d" {
+		t.Errorf("FilterVersionKind() = %+v", got)
+	}
+}
@@ -0,0 +1,313 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// NestedBinary 表示嵌入在另一个文件中的Go二进制文件，例如通过 //go:embed 打包的辅助程序
+// 示例：
+//
+//	{
+//	  "offset": 8388608,
+//	  "size": 2097152,
+//	  "build_info_offset": 8392704,
+//	  "header_found": true,
+//	  "info": {"path": "github.com/example/agent", ...}
+//	}
+type NestedBinary struct {
+	Offset          int64       `json:"offset"`            // 嵌入的可执行文件头在外层文件中的字节偏移；没有找到文件头时为构建信息块的偏移
+	Size            int64       `json:"size,omitempty"`    // 根据节和段估算的可执行文件大小，没有找到文件头时为0
+	BuildInfoOffset int64       `json:"build_info_offset"` // 构建信息块在外层文件中的字节偏移，无法确定时为-1
+	HeaderFound     bool        `json:"header_found"`      // 是否找到并解析了可执行文件头，为false时只有构建信息，没有格式信息
+	Info            *BinaryInfo `json:"info"`              // 嵌入的二进制文件的解析结果，它嵌入的二进制文件在 Info.Nested 中
+}
+
+// executableMagics 是深度扫描时查找的可执行文件头魔数：ELF、PE（MZ头）和单架构Mach-O
+var executableMagics = [][]byte{
+	[]byte("\x7FELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+}
+
+// ScanNestedBinaries 在整个文件中搜索嵌入的Go二进制文件。
+// 扫描会查找可执行文件头和构建信息魔数：能解析出文件头和构建信息的位置作为完整的嵌入二进制文件返回，
+// 不属于任何可执行文件的构建信息块（Go 1.18及以上版本的内联格式）也会单独返回。
+// 外层文件本身的构建信息不会被返回。嵌入的二进制文件中再嵌入的二进制文件位于 Info.Nested 中。
+//
+// 参数:
+//   - r: 文件内容的读取器
+//   - size: 文件的字节数
+//
+// 返回:
+//   - []NestedBinary: 按偏移排序的嵌入二进制文件，没有找到时为空
+//   - error: 如果读取文件失败，则返回错误信息
+//
+// 使用示例:
+//
+//	f, _ := os.Open("/usr/local/bin/installer")
+//	stat, _ := f.Stat()
+//	nested, err := gobinaryparser.ScanNestedBinaries(f, stat.Size())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, n := range nested {
+//		fmt.Printf("偏移 %d: %s@%s\n", n.Offset, n.Info.Path, n.Info.Version)
+//	}
+func ScanNestedBinaries(r io.ReaderAt, size int64) ([]NestedBinary, error) {
+	return scanNested(r, size, "", "reader")
+}
+
+// scanNested 搜索嵌入的Go二进制文件，path和sourceType写入每个嵌入二进制文件的解析结果
+func scanNested(r io.ReaderAt, size int64, path string, sourceType string) ([]NestedBinary, error) {
+	headers, blocks, err := scanMagics(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// 属于某个可执行文件的构建信息块，不单独报告
+	owned := make(map[int64]bool)
+	if exe, err := openExecutable(r); err == nil {
+		if offset := exe.buildInfoOffset(); offset >= 0 {
+			owned[offset] = true
+		}
+	}
+
+	var found []NestedBinary
+	for _, offset := range headers {
+		sr := io.NewSectionReader(r, offset, size-offset)
+		exe, err := openExecutable(sr)
+		if err != nil {
+			continue
+		}
+		bi, err := buildinfo.Read(sr)
+		if err != nil {
+			continue
+		}
+		info, err := createBinaryInfo(bi, sr, path, sourceType)
+		if err != nil {
+			continue
+		}
+
+		info.RawBuildInfo.shift(offset)
+		nested := NestedBinary{Offset: offset, Size: exe.extent(), BuildInfoOffset: -1, HeaderFound: true, Info: info}
+		if bo := exe.buildInfoOffset(); bo >= 0 {
+			nested.BuildInfoOffset = offset + bo
+			owned[offset+bo] = true
+		}
+		found = append(found, nested)
+	}
+
+	for _, offset := range blocks {
+		if owned[offset] {
+			continue
+		}
+		bi, err := readInlineBuildInfo(r, offset)
+		if err != nil {
+			continue
+		}
+		info, err := createBinaryInfo(bi, nil, path, sourceType)
+		if err != nil {
+			continue
+		}
+		info.RawBuildInfo, _ = parseRawBuildInfo(r, offset, nil)
+		found = append(found, NestedBinary{Offset: offset, BuildInfoOffset: offset, Info: info})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Offset < found[j].Offset })
+	return nestBinaries(found), nil
+}
+
+// nestBinaries 将按偏移排序的嵌入二进制文件列表按包含关系组织成树
+func nestBinaries(flat []NestedBinary) []NestedBinary {
+	root := &BinaryInfo{}
+	type frame struct {
+		info *BinaryInfo
+		end  int64
+	}
+	stack := []frame{{info: root, end: math.MaxInt64}}
+
+	for _, n := range flat {
+		for len(stack) > 1 && n.Offset >= stack[len(stack)-1].end {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].info
+		parent.Nested = append(parent.Nested, n)
+		if n.Size > 0 {
+			stack = append(stack, frame{info: n.Info, end: n.Offset + n.Size})
+		}
+	}
+	return root.Nested
+}
+
+// scanMagics 在整个文件中查找可执行文件头和构建信息魔数，返回按偏移排序的可执行文件头候选位置
+// （不包括偏移0处外层文件本身的文件头）和构建信息块的位置
+func scanMagics(r io.ReaderAt, size int64) (headers []int64, blocks []int64, err error) {
+	overlap := int64(len(buildInfoMagic) - 1)
+	seen := make(map[int64]bool)
+
+	buf := make([]byte, scanChunkSize)
+	for offset := int64(0); offset < size; offset += scanChunkSize - overlap {
+		n, err := r.ReadAt(buf, offset)
+		if n == 0 && err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		chunk := buf[:n]
+
+		for _, magic := range executableMagics {
+			for i := 0; ; {
+				j := bytes.Index(chunk[i:], magic)
+				if j < 0 {
+					break
+				}
+				pos := offset + int64(i+j)
+				i += j + 1
+				if pos == 0 || seen[pos] {
+					continue
+				}
+				if magic[0] == 'M' && !isPEHeader(r, pos) {
+					continue
+				}
+				seen[pos] = true
+				headers = append(headers, pos)
+			}
+		}
+
+		for i := 0; ; {
+			j := bytes.Index(chunk[i:], buildInfoMagic)
+			if j < 0 {
+				break
+			}
+			pos := offset + int64(i+j)
+			i += j + 1
+			// 构建信息块按16字节对齐
+			if pos%16 == 0 && !seen[pos] {
+				seen[pos] = true
+				blocks = append(blocks, pos)
+			}
+		}
+
+		if int64(n) < scanChunkSize {
+			break
+		}
+	}
+
+	sort.Slice(headers, func(i, j int) bool { return headers[i] < headers[j] })
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+	return headers, blocks, nil
+}
+
+// isPEHeader 判断offset处的MZ头是否指向有效的PE签名，用于过滤数据中大量出现的"MZ"
+func isPEHeader(r io.ReaderAt, offset int64) bool {
+	var lfanew [4]byte
+	if _, err := r.ReadAt(lfanew[:], offset+0x3c); err != nil {
+		return false
+	}
+	peOffset := int64(binary.LittleEndian.Uint32(lfanew[:]))
+	if peOffset < 0x40 || peOffset > 1<<16 {
+		return false
+	}
+	var sig [4]byte
+	if _, err := r.ReadAt(sig[:], offset+peOffset); err != nil {
+		return false
+	}
+	return string(sig[:]) == "PE\x00\x00"
+}
+
+// buildInfoOffset 返回可执行文件自身的构建信息块在文件中的偏移，无法确定时返回-1
+func (e *executable) buildInfoOffset() int64 {
+	switch e.format {
+	case FormatELF:
+		if s := e.elf.Section(".go.buildinfo"); s != nil && s.Type != elf.SHT_NOBITS {
+			return int64(s.Offset)
+		}
+	case FormatMachO:
+		if s := e.macho.Section("__go_buildinfo"); s != nil {
+			return int64(s.Offset)
+		}
+	case FormatPE:
+		// PE文件没有单独的构建信息节，构建信息位于.data节的开头附近
+		for _, s := range e.pe.Sections {
+			if s.Name != ".data" {
+				continue
+			}
+			// 跳过.data中未对齐或无法解析的魔数字符串，例如链接了debug/buildinfo的程序中的字面量
+			offset, err := findBuildInfo(io.NewSectionReader(e.r, int64(s.Offset), int64(s.Size)), int64(s.Size), buildInfoAlign, func(offset int64) bool {
+				_, err := parseRawBuildInfo(e.r, int64(s.Offset)+offset, e)
+				return err == nil
+			})
+			if err == nil && offset >= 0 {
+				return int64(s.Offset) + offset
+			}
+		}
+	}
+	return -1
+}
+
+// extent 根据节、段和节头表估算可执行文件占用的字节数，无法估算时返回0
+func (e *executable) extent() int64 {
+	var end uint64
+	grow := func(offset, size uint64) {
+		if offset+size > end {
+			end = offset + size
+		}
+	}
+
+	switch e.format {
+	case FormatELF:
+		for _, p := range e.elf.Progs {
+			grow(p.Off, p.Filesz)
+		}
+		for _, s := range e.elf.Sections {
+			if s.Type != elf.SHT_NOBITS {
+				grow(s.Offset, s.FileSize)
+			}
+		}
+		grow(e.elfSectionHeaderTable())
+	case FormatMachO:
+		for _, l := range e.macho.Loads {
+			if s, ok := l.(*macho.Segment); ok {
+				grow(s.Offset, s.Filesz)
+			}
+		}
+	case FormatPE:
+		for _, s := range e.pe.Sections {
+			grow(uint64(s.Offset), uint64(s.Size))
+		}
+	}
+	return int64(end)
+}
+
+// elfSectionHeaderTable 返回ELF节头表的偏移和字节数，debug/elf 没有导出这些字段，因此直接读取文件头
+func (e *executable) elfSectionHeaderTable() (offset, size uint64) {
+	header := make([]byte, 64)
+	if _, err := e.r.ReadAt(header, 0); err != nil {
+		return 0, 0
+	}
+	order := e.byteOrder()
+	if e.elf.Class == elf.ELFCLASS64 {
+		return order.Uint64(header[0x28:]), uint64(order.Uint16(header[0x3a:])) * uint64(order.Uint16(header[0x3c:]))
+	}
+	return uint64(order.Uint32(header[0x20:])), uint64(order.Uint16(header[0x2e:])) * uint64(order.Uint16(header[0x30:]))
+}
+
+// readerSize 返回读取器中数据的字节数，无法确定时返回-1
+func readerSize(r io.ReaderAt) int64 {
+	switch v := r.(type) {
+	case interface{ Size() int64 }:
+		return v.Size()
+	case *os.File:
+		if stat, err := v.Stat(); err == nil {
+			return stat.Size()
+		}
+	}
+	return -1
+}
@@ -0,0 +1,128 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"testing"
+)
+
+// embedBinary 将inner追加到outer之后（按4096字节对齐），返回拼接后的数据和inner的偏移
+func embedBinary(outer, inner []byte) ([]byte, int64) {
+	offset := (len(outer) + 4095) &^ 4095
+	data := make([]byte, offset, offset+len(inner))
+	copy(data, outer)
+	return append(data, inner...), int64(offset)
+}
+
+func TestScanNestedBinaries(t *testing.T) {
+	data := readTestBinary(t)
+
+	nested, err := ScanNestedBinaries(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ScanNestedBinaries() error = %v", err)
+	}
+	if len(nested) != 0 {
+		t.Fatalf("Expected no nested binaries in the test binary, got %d at offset %d", len(nested), nested[0].Offset)
+	}
+
+	// 追加在可执行文件结构之后的数据不属于前一个二进制文件，两个嵌入的二进制文件处于同一层
+	second, secondOffset := embedBinary(data, data)
+	combined, firstOffset := embedBinary(data, second)
+
+	nested, err = ScanNestedBinaries(bytes.NewReader(combined), int64(len(combined)))
+	if err != nil {
+		t.Fatalf("ScanNestedBinaries() error = %v", err)
+	}
+	if len(nested) != 2 {
+		t.Fatalf("Expected 2 nested binaries, got %d", len(nested))
+	}
+	if nested[0].Offset != firstOffset || nested[1].Offset != firstOffset+secondOffset {
+		t.Errorf("Offsets = %d, %d, want %d, %d", nested[0].Offset, nested[1].Offset, firstOffset, firstOffset+secondOffset)
+	}
+	for _, n := range nested {
+		if !n.HeaderFound || n.Info == nil || n.Info.Format == nil || len(n.Info.Nested) != 0 {
+			t.Fatalf("Unexpected nested binary: %+v", n)
+		}
+		if n.Size <= 0 || n.Size > int64(len(data)) {
+			t.Errorf("Size = %d, want (0, %d]", n.Size, len(data))
+		}
+		if n.BuildInfoOffset <= n.Offset || n.BuildInfoOffset >= n.Offset+n.Size {
+			t.Errorf("BuildInfoOffset = %d, want inside [%d, %d)", n.BuildInfoOffset, n.Offset, n.Offset+n.Size)
+		}
+		if n.Info.GoVersion == "" {
+			t.Error("Expected Go version of nested binary")
+		}
+	}
+}
+
+func TestNestBinaries(t *testing.T) {
+	flat := []NestedBinary{
+		{Offset: 100, Size: 1000, Info: &BinaryInfo{Path: "a"}},
+		{Offset: 200, Size: 300, Info: &BinaryInfo{Path: "a/b"}},
+		{Offset: 250, Info: &BinaryInfo{Path: "a/b/block"}},
+		{Offset: 600, Size: 100, Info: &BinaryInfo{Path: "a/c"}},
+		{Offset: 2000, Size: 100, Info: &BinaryInfo{Path: "d"}},
+	}
+
+	tree := nestBinaries(flat)
+	if len(tree) != 2 || tree[0].Info.Path != "a" || tree[1].Info.Path != "d" {
+		t.Fatalf("Unexpected top level: %+v", tree)
+	}
+	a := tree[0].Info
+	if len(a.Nested) != 2 || a.Nested[0].Info.Path != "a/b" || a.Nested[1].Info.Path != "a/c" {
+		t.Fatalf("Unexpected children of a: %+v", a.Nested)
+	}
+	if b := a.Nested[0].Info; len(b.Nested) != 1 || b.Nested[0].Info.Path != "a/b/block" {
+		t.Errorf("Unexpected children of a/b: %+v", b.Nested)
+	}
+}
+
+func TestScanNestedBinaries_BuildInfoBlock(t *testing.T) {
+	data := readTestBinary(t)
+	exe, err := openExecutable(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("openExecutable() error = %v", err)
+	}
+	bo := exe.buildInfoOffset()
+	if bo < 0 {
+		t.Skip("test binary has no build info section")
+	}
+
+	// 只嵌入构建信息块，没有可执行文件头
+	block := data[bo:]
+	if len(block) > 64<<10 {
+		block = block[:64<<10]
+	}
+	combined, offset := embedBinary(data, block)
+
+	nested, err := ScanNestedBinaries(bytes.NewReader(combined), int64(len(combined)))
+	if err != nil {
+		t.Fatalf("ScanNestedBinaries() error = %v", err)
+	}
+	if len(nested) != 1 {
+		t.Fatalf("Expected 1 build info block, got %d", len(nested))
+	}
+	if n := nested[0]; n.Offset != offset || n.BuildInfoOffset != offset || n.HeaderFound || n.Info.GoVersion == "" {
+		t.Errorf("Unexpected build info block: %+v", n)
+	}
+}
+
+func TestParser_DeepScan(t *testing.T) {
+	data := readTestBinary(t)
+	combined, offset := embedBinary(data, data)
+
+	info, err := NewParser().ParseBinaryFromBytes(combined)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromBytes() error = %v", err)
+	}
+	if len(info.Nested) != 0 {
+		t.Error("Expected no nested binaries without deep scan")
+	}
+
+	info, err = NewParser(WithDeepScan(true)).ParseBinaryFromBytes(combined)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromBytes() error = %v", err)
+	}
+	if len(info.Nested) != 1 || info.Nested[0].Offset != offset {
+		t.Errorf("Nested = %+v, want one binary at offset %d", info.Nested, offset)
+	}
+}
@@ -0,0 +1,478 @@
+package gobinaryparser
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout 是不带上下文的远程解析方法使用的默认超时时间
+const DefaultTimeout = 30 * time.Second
+
+// Parser 是可配置的Go二进制文件解析器，通过 NewParser 和选项函数创建。
+// 包级别的解析函数都使用默认配置的Parser，需要自定义HTTP客户端、下载大小限制、
+// 超时、日志、缓存或重试策略时应创建自己的Parser。Parser可以被多个goroutine同时使用。
+//
+// 使用示例:
+//
+//	parser := gobinaryparser.NewParser(
+//		gobinaryparser.WithHTTPClient(&http.Client{Transport: transport}),
+//		gobinaryparser.WithMaxDownloadSize(256<<20),
+//		gobinaryparser.WithTimeout(time.Minute),
+//		gobinaryparser.WithUserAgent("my-scanner/1.0"),
+//		gobinaryparser.WithBearerToken(os.Getenv("ARTIFACT_TOKEN")),
+//		gobinaryparser.WithRetryPolicy(gobinaryparser.RetryPolicy{MaxAttempts: 3}),
+//		gobinaryparser.WithCache(gobinaryparser.NewMemoryCache()),
+//	)
+//	info, err := parser.ParseBinaryFromURL("https://example.com/binaries/kubectl")
+type Parser struct {
+	client          *http.Client
+	maxDownloadSize int64
+	timeout         time.Duration
+	logger          *slog.Logger
+	cache           Cache
+	userAgent       string
+	retry           RetryPolicy
+	deepScan        bool
+	httpCache       HTTPCacheOptions
+	headers         http.Header
+	basicAuth       *basicAuth
+	cookies         []*http.Cookie
+	tlsConfig       *tls.Config
+	headProbe       bool
+	progress        ProgressFunc
+}
+
+// basicAuth 是HTTP基本认证的用户名和密码
+type basicAuth struct {
+	username, password string
+}
+
+// Option 是配置 Parser 的选项函数
+type Option func(*Parser)
+
+// NewParser 创建使用给定选项的Parser，未设置的选项使用默认值：
+// http.DefaultClient、DefaultMaxStreamSize、DefaultTimeout、不记录日志、不缓存、不重试。
+//
+// 参数:
+//   - opts: 选项函数列表
+//
+// 返回:
+//   - *Parser: 新创建的解析器
+//
+// 使用示例:
+//
+//	parser := gobinaryparser.NewParser(gobinaryparser.WithTimeout(10 * time.Second))
+//	info, err := parser.ParseBinaryFromRemoteFile("https://example.com/binaries/large-binary")
+func NewParser(opts ...Option) *Parser {
+	p := &Parser{
+		client:          http.DefaultClient,
+		maxDownloadSize: DefaultMaxStreamSize,
+		timeout:         DefaultTimeout,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.tlsConfig != nil {
+		p.client = p.clientWithTLS(p.client, p.tlsConfig)
+	}
+	return p
+}
+
+// defaultParser 是包级别解析函数使用的解析器
+var defaultParser = NewParser()
+
+// WithHTTPClient 设置下载远程文件使用的HTTP客户端，为nil时使用 http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Parser) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+		p.client = client
+	}
+}
+
+// WithMaxDownloadSize 设置从URL下载或从数据流读取时允许的最大字节数，
+// 超过时返回 ErrTooLarge，<=0 时使用 DefaultMaxStreamSize
+func WithMaxDownloadSize(size int64) Option {
+	return func(p *Parser) {
+		if size <= 0 {
+			size = DefaultMaxStreamSize
+		}
+		p.maxDownloadSize = size
+	}
+}
+
+// WithTimeout 设置不带上下文的远程解析方法（ParseBinaryFromURL、ParseBinaryFromRemoteFile）的超时时间，
+// <=0 表示不设置超时。带上下文的方法使用调用者提供的上下文控制超时。
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *Parser) {
+		p.timeout = timeout
+	}
+}
+
+// WithLogger 设置记录HTTP请求、重试、缓存命中和降级解析的日志记录器，为nil时不记录日志
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Parser) {
+		if logger == nil {
+			logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		}
+		p.logger = logger
+	}
+}
+
+// WithCache 设置解析结果缓存，为nil时不缓存。
+// 本地文件以路径、大小和修改时间作为键，远程文件以URL作为键；从字节和读取器解析的结果不缓存。
+func WithCache(cache Cache) Option {
+	return func(p *Parser) {
+		p.cache = cache
+	}
+}
+
+// WithUserAgent 设置HTTP请求的User-Agent头，为空时使用HTTP客户端的默认值
+func WithUserAgent(userAgent string) Option {
+	return func(p *Parser) {
+		p.userAgent = userAgent
+	}
+}
+
+// WithProgress 设置下载进度的回调函数，为nil时不报告进度。
+// ParseBinaryFromURL 下载文件以及 ParseBinaryFromRemoteFile 在服务器不支持范围请求而下载整个文件时报告进度，
+// 超过 WithMaxDownloadSize 的下载会在Content-Length超限时直接中止，或在读取到超出限制的数据时中止。
+func WithProgress(fn ProgressFunc) Option {
+	return func(p *Parser) {
+		p.progress = fn
+	}
+}
+
+// WithHEADProbe 设置 HTTPReaderAt 是否在第一次范围请求前发送HEAD请求，预先获取文件大小、ETag和Accept-Ranges。
+// 默认不探测，也就不会预先发现服务器不支持范围请求：第一个范围请求的响应同样可以验证范围支持，
+// 服务器忽略Range头返回200时响应体直接作为完整文件使用。
+// 开启探测后，HEAD响应声明 Accept-Ranges: none 时直接下载整个文件而不发送范围请求；
+// 探测还可以在第一次读取时就发现不存在的文件，并为所有范围请求验证ETag。
+func WithHEADProbe(enabled bool) Option {
+	return func(p *Parser) {
+		p.headProbe = enabled
+	}
+}
+
+// WithHeaders 为所有HTTP请求添加请求头，多次调用时合并
+func WithHeaders(headers http.Header) Option {
+	return func(p *Parser) {
+		if p.headers == nil {
+			p.headers = make(http.Header)
+		}
+		for key, values := range headers {
+			for _, value := range values {
+				p.headers.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithBearerToken 为所有HTTP请求设置 "Authorization: Bearer <token>" 请求头，为空时不设置
+func WithBearerToken(token string) Option {
+	return func(p *Parser) {
+		if token == "" {
+			return
+		}
+		if p.headers == nil {
+			p.headers = make(http.Header)
+		}
+		p.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithBasicAuth 为所有HTTP请求设置HTTP基本认证
+func WithBasicAuth(username, password string) Option {
+	return func(p *Parser) {
+		p.basicAuth = &basicAuth{username: username, password: password}
+	}
+}
+
+// WithCookies 为所有HTTP请求添加Cookie。需要由服务器设置和更新的Cookie应通过 WithHTTPClient 提供带Jar的客户端。
+func WithCookies(cookies ...*http.Cookie) Option {
+	return func(p *Parser) {
+		p.cookies = append(p.cookies, cookies...)
+	}
+}
+
+// WithTLSConfig 设置HTTPS请求的TLS配置，用于自定义CA证书和客户端证书（mTLS），可以用 LoadTLSConfig 从文件加载。
+// 解析器复制HTTP客户端及其 *http.Transport 并替换TLS配置，不会修改 WithHTTPClient 提供的客户端或 http.DefaultClient；
+// 客户端的Transport不是 *http.Transport 时TLS配置被忽略，应在自己的Transport中设置。
+func WithTLSConfig(config *tls.Config) Option {
+	return func(p *Parser) {
+		p.tlsConfig = config
+	}
+}
+
+// LoadTLSConfig 从PEM文件加载TLS配置。
+// caFile是额外信任的CA证书包，会与系统证书一起使用；certFile和keyFile是mTLS的客户端证书和私钥，必须同时提供。
+// 为空的参数被忽略。
+//
+// 参数:
+//   - caFile: CA证书包文件路径
+//   - certFile: 客户端证书文件路径
+//   - keyFile: 客户端私钥文件路径
+//
+// 返回:
+//   - *tls.Config: 加载的TLS配置
+//   - error: 如果文件无法读取或证书无效，则返回错误信息
+//
+// 使用示例:
+//
+//	config, err := gobinaryparser.LoadTLSConfig("/etc/pki/internal-ca.pem", "client.pem", "client-key.pem")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	parser := gobinaryparser.NewParser(gobinaryparser.WithTLSConfig(config))
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取CA证书失败: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA证书文件 %s 中没有有效的PEM证书", caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("客户端证书和私钥必须同时提供")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// clientWithTLS 返回使用给定TLS配置的客户端副本
+func (p *Parser) clientWithTLS(client *http.Client, config *tls.Config) *http.Client {
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		p.logger.Warn("HTTP客户端的Transport不是*http.Transport，忽略TLS配置", "transport", fmt.Sprintf("%T", t))
+		return client
+	}
+	transport.TLSClientConfig = config.Clone()
+
+	c := *client
+	c.Transport = transport
+	return &c
+}
+
+// WithRetryPolicy 设置HTTP请求失败时的重试策略
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(p *Parser) {
+		p.retry = policy
+	}
+}
+
+// WithDeepScan 设置是否在解析后搜索嵌入的Go二进制文件（例如通过 //go:embed 打包的辅助程序），
+// 结果保存在 BinaryInfo.Nested 中。深度扫描需要读取整个文件，
+// 因此不适用于 ParseBinaryFromRemoteFile 以及无法确定大小的 io.ReaderAt。
+func WithDeepScan(enabled bool) Option {
+	return func(p *Parser) {
+		p.deepScan = enabled
+	}
+}
+
+// WithHTTPCache 设置 HTTPReaderAt 和 ParseBinaryFromRemoteFile 使用的块大小、预读块数和缓存容量，
+// 未设置的字段使用默认值，参见 HTTPCacheOptions
+func WithHTTPCache(opts HTTPCacheOptions) Option {
+	return func(p *Parser) {
+		p.httpCache = opts
+	}
+}
+
+// timeoutContext 为不带上下文的方法创建带超时的上下文
+func (p *Parser) timeoutContext() (context.Context, context.CancelFunc) {
+	if p.timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), p.timeout)
+}
+
+// cached 从缓存中查找解析结果
+func (p *Parser) cached(key string) (*BinaryInfo, bool) {
+	if p.cache == nil || key == "" {
+		return nil, false
+	}
+	info, ok := p.cache.Get(key)
+	if ok {
+		p.logger.Debug("缓存命中", "key", key)
+	}
+	return info, ok
+}
+
+// store 将解析结果写入缓存，并记录降级解析
+func (p *Parser) store(key string, info *BinaryInfo) {
+	if info.Degraded != nil {
+		p.logger.Info("缺少构建信息，以降级模式解析", "source", info.FilePath, "reason", info.Degraded.Reason)
+	}
+	if p.cache != nil && key != "" {
+		p.cache.Set(key, info)
+	}
+}
+
+// RetryPolicy 控制HTTP请求失败时的重试。
+// 网络错误、429、500、502、503和504状态码以及中断的范围请求响应会被重试（501等其他状态码直接返回错误），每次重试前的等待时间从InitialBackoff开始翻倍，
+// 不超过MaxBackoff，并随机减少最多Jitter比例以避免多个客户端同时重试。
+// 响应带有Retry-After头时按服务器要求的时间等待；等待会超过上下文的截止时间时不再重试。
+// 中断的范围请求从已读取的位置继续请求剩余的部分。
+type RetryPolicy struct {
+	MaxAttempts    int           // 最大尝试次数（包括第一次请求），<=1 表示不重试
+	InitialBackoff time.Duration // 第一次重试前的等待时间，<=0 时使用500毫秒
+	MaxBackoff     time.Duration // 等待时间的上限，<=0 时使用10秒
+	Jitter         float64       // 等待时间随机减少的最大比例（0到1），0 时使用0.2，<0 表示不抖动
+}
+
+// attempts 返回生效的最大尝试次数
+func (r RetryPolicy) attempts() int {
+	if r.MaxAttempts < 1 {
+		return 1
+	}
+	return r.MaxAttempts
+}
+
+// backoff 返回第attempt次重试（从1开始）前不含抖动的等待时间
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	initial, max := r.InitialBackoff, r.MaxBackoff
+	if initial <= 0 {
+		initial = 500 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+	d := initial
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// delay 返回第attempt次重试前实际的等待时间：resp带有Retry-After头时使用服务器要求的时间，否则为带抖动的退避时间
+func (r RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		return d
+	}
+	d := r.backoff(attempt)
+	jitter := r.Jitter
+	switch {
+	case jitter == 0:
+		jitter = 0.2
+	case jitter < 0:
+		jitter = 0
+	case jitter > 1:
+		jitter = 1
+	}
+	return d - time.Duration(jitter*rand.Float64()*float64(d))
+}
+
+// retryAfter 解析响应的Retry-After头，支持秒数和HTTP日期两种形式
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleepContext 等待d，上下文被取消时提前返回上下文的错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Cache 是解析结果的缓存，实现必须可以被多个goroutine同时使用。
+// 缓存的 *BinaryInfo 会被多次返回，调用者不应修改它。
+type Cache interface {
+	// Get 返回键对应的解析结果
+	Get(key string) (*BinaryInfo, bool)
+	// Set 保存键对应的解析结果
+	Set(key string, info *BinaryInfo)
+}
+
+// MemoryCache 是保存在内存中的 Cache 实现，没有容量限制
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]*BinaryInfo
+}
+
+// NewMemoryCache 创建空的内存缓存
+//
+// 返回:
+//   - *MemoryCache: 新创建的缓存
+//
+// 使用示例:
+//
+//	parser := gobinaryparser.NewParser(gobinaryparser.WithCache(gobinaryparser.NewMemoryCache()))
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*BinaryInfo)}
+}
+
+// Get 实现 Cache 接口
+func (c *MemoryCache) Get(key string) (*BinaryInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info, ok := c.entries[key]
+	return info, ok
+}
+
+// Set 实现 Cache 接口
+func (c *MemoryCache) Set(key string, info *BinaryInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = info
+}
@@ -0,0 +1,393 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBinaryServer 启动提供测试二进制文件的HTTP服务器，fail返回true时响应503
+func newBinaryServer(t *testing.T, requests *int32, fail func(n int32) bool) *httptest.Server {
+	t.Helper()
+
+	data := readTestBinary(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(requests, 1)
+		if fail != nil && fail(n) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewParser_Defaults(t *testing.T) {
+	p := NewParser()
+	if p.client != http.DefaultClient {
+		t.Error("Expected http.DefaultClient by default")
+	}
+	if p.maxDownloadSize != DefaultMaxStreamSize || p.timeout != DefaultTimeout {
+		t.Errorf("maxDownloadSize = %d, timeout = %v, want defaults", p.maxDownloadSize, p.timeout)
+	}
+	if p.retry.attempts() != 1 || p.cache != nil || p.logger == nil {
+		t.Errorf("Unexpected defaults: %+v", p)
+	}
+}
+
+func TestParser_UserAgentAndMaxDownloadSize(t *testing.T) {
+	var userAgent atomic.Value
+	data := readTestBinary(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.UserAgent())
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	p := NewParser(WithUserAgent("godeps-test/1.0"))
+	if _, err := p.ParseBinaryFromURL(server.URL); err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+	if got := userAgent.Load(); got != "godeps-test/1.0" {
+		t.Errorf("User-Agent = %v, want godeps-test/1.0", got)
+	}
+
+	if _, err := p.ParseBinaryFromRemoteFile(server.URL); err != nil {
+		t.Fatalf("ParseBinaryFromRemoteFile() error = %v", err)
+	}
+	if got := userAgent.Load(); got != "godeps-test/1.0" {
+		t.Errorf("User-Agent of range request = %v, want godeps-test/1.0", got)
+	}
+
+	small := NewParser(WithMaxDownloadSize(1024))
+	if _, err := small.ParseBinaryFromURL(server.URL); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ParseBinaryFromURL() error = %v, want ErrTooLarge", err)
+	}
+	if _, err := small.ParseBinaryFromStream(context.Background(), bytes.NewReader(data), nil); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ParseBinaryFromStream() error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestParser_RetryPolicy(t *testing.T) {
+	var requests int32
+	server := newBinaryServer(t, &requests, func(n int32) bool { return n <= 2 })
+
+	// 默认不重试
+	if _, err := NewParser().ParseBinaryFromURL(server.URL); err == nil {
+		t.Fatal("Expected error without retries")
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	p := NewParser(WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	info, err := p.ParseBinaryFromURL(server.URL)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+	if info.GoVersion == "" {
+		t.Error("Expected Go version")
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Server received %d requests, want 3", got)
+	}
+}
+
+func TestParser_RetryStatusCodes(t *testing.T) {
+	for status, retried := range map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+		http.StatusNotImplemented:      false,
+		http.StatusForbidden:           false,
+	} {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(status)
+		}))
+
+		p := NewParser(WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+		_, err := p.ParseBinaryFromURL(server.URL)
+		server.Close()
+
+		want := int32(1)
+		if retried {
+			want = 3
+		}
+		var rerr *RetryError
+		if err == nil || errors.As(err, &rerr) != retried || atomic.LoadInt32(&requests) != want {
+			t.Errorf("status %d: %d requests, error = %v, want %d requests", status, requests, err, want)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, w := range want {
+		if got := policy.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+	if (RetryPolicy{}).backoff(1) != 500*time.Millisecond {
+		t.Error("Expected 500ms default initial backoff")
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond}
+	for i := 0; i < 20; i++ {
+		if d := policy.delay(1, nil); d < 80*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("delay(1) = %v, want between 80ms and 100ms with the default jitter", d)
+		}
+	}
+	if d := (RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: -1}).delay(1, nil); d != 100*time.Millisecond {
+		t.Errorf("delay(1) without jitter = %v, want 100ms", d)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+	if d := policy.delay(1, resp); d != 7*time.Second {
+		t.Errorf("delay() with Retry-After: 7 = %v, want 7s", d)
+	}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if d := policy.delay(1, resp); d < 58*time.Second || d > time.Minute {
+		t.Errorf("delay() with an HTTP date Retry-After = %v, want about 1m", d)
+	}
+	resp.Header.Set("Retry-After", "soon")
+	if d := policy.delay(1, resp); d > 100*time.Millisecond {
+		t.Errorf("delay() with an invalid Retry-After = %v, want the backoff", d)
+	}
+}
+
+func TestParser_RetryError(t *testing.T) {
+	var requests int32
+	server := newBinaryServer(t, &requests, func(int32) bool { return true })
+
+	p := NewParser(WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	for name, parse := range map[string]func(string) (*BinaryInfo, error){
+		"url":    p.ParseBinaryFromURL,
+		"remote": p.ParseBinaryFromRemoteFile,
+	} {
+		atomic.StoreInt32(&requests, 0)
+		_, err := parse(server.URL)
+		var rerr *RetryError
+		if !errors.As(err, &rerr) || rerr.Attempts != 3 || !strings.Contains(err.Error(), "共尝试3次") {
+			t.Errorf("%s: error = %v, want *RetryError with 3 attempts", name, err)
+		}
+		if got := atomic.LoadInt32(&requests); got != 3 {
+			t.Errorf("%s: server received %d requests, want 3", name, got)
+		}
+	}
+}
+
+func TestParser_RetryAfterDeadline(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	p := NewParser(WithRetryPolicy(RetryPolicy{MaxAttempts: 5}))
+	_, err := p.ParseBinaryFromURLWithContext(ctx, server.URL)
+	var rerr *RetryError
+	if !errors.As(err, &rerr) || rerr.Attempts != 1 {
+		t.Errorf("error = %v, want *RetryError after 1 attempt", err)
+	}
+	if time.Since(start) > time.Second || atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("Retried a request whose Retry-After exceeds the deadline")
+	}
+}
+
+func TestParser_Cache(t *testing.T) {
+	var requests int32
+	server := newBinaryServer(t, &requests, nil)
+
+	var logs bytes.Buffer
+	p := NewParser(
+		WithCache(NewMemoryCache()),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+
+	first, err := p.ParseBinaryFromURL(server.URL)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+	second, err := p.ParseBinaryFromURL(server.URL)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); first != second || got != 1 {
+		t.Errorf("Expected cached result, server received %d requests", got)
+	}
+	if !strings.Contains(logs.String(), "缓存命中") {
+		t.Errorf("Expected cache hit to be logged, got:\n%s", logs.String())
+	}
+
+	path := testBinaryPath(t)
+	a, err := p.ParseBinary(path)
+	if err != nil {
+		t.Fatalf("ParseBinary() error = %v", err)
+	}
+	b, err := p.ParseBinaryFromFile(path)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromFile() error = %v", err)
+	}
+	if a != b {
+		t.Error("Expected cached result for the same file")
+	}
+}
+
+func TestParser_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	p := NewParser(WithTimeout(50 * time.Millisecond))
+	if _, err := p.ParseBinaryFromURL(server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ParseBinaryFromURL() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestParser_RemoteFileContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	p := NewParser(WithTimeout(0))
+	if _, err := p.ParseBinaryFromRemoteFileWithContext(ctx, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ParseBinaryFromRemoteFileWithContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestParser_HeadersAndAuth(t *testing.T) {
+	data := readTestBinary(t)
+	var failures atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		cookie, err := r.Cookie("session")
+		if !ok || user != "ci" || pass != "secret" || r.Header.Get("X-Team") != "build" || err != nil || cookie.Value != "abc" {
+			failures.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	p := NewParser(
+		WithHeaders(http.Header{"X-Team": {"build"}}),
+		WithBasicAuth("ci", "secret"),
+		WithCookies(&http.Cookie{Name: "session", Value: "abc"}),
+	)
+	if _, err := p.ParseBinaryFromURL(server.URL); err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+	if _, err := p.ParseBinaryFromRemoteFile(server.URL); err != nil {
+		t.Fatalf("ParseBinaryFromRemoteFile() error = %v", err)
+	}
+	if _, err := NewParser().ParseBinaryFromURL(server.URL); err == nil || failures.Load() != 1 {
+		t.Errorf("ParseBinaryFromURL() without credentials error = %v, want HTTP 401", err)
+	}
+
+	var auth atomic.Value
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	defer tokenServer.Close()
+
+	if _, err := NewParser(WithBearerToken("t0ken")).ParseBinaryFromRemoteFile(tokenServer.URL); err != nil {
+		t.Fatalf("ParseBinaryFromRemoteFile() error = %v", err)
+	}
+	if got := auth.Load(); got != "Bearer t0ken" {
+		t.Errorf("Authorization = %v, want \"Bearer t0ken\"", got)
+	}
+}
+
+func TestParser_TLSConfig(t *testing.T) {
+	data := readTestBinary(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// 服务器的自签名证书同时用作CA证书和客户端证书
+	dir := t.TempDir()
+	cert := server.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writePEM(t, certFile, "CERTIFICATE", cert.Certificate[0])
+	writePEM(t, keyFile, "PRIVATE KEY", key)
+
+	if _, err := NewParser().ParseBinaryFromURL(server.URL); err == nil {
+		t.Error("ParseBinaryFromURL() succeeded without the CA certificate")
+	}
+
+	defaultTLS := http.DefaultTransport.(*http.Transport).TLSClientConfig
+	config, err := LoadTLSConfig(certFile, certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadTLSConfig() error = %v", err)
+	}
+	p := NewParser(WithTLSConfig(config))
+	if _, err := p.ParseBinaryFromRemoteFile(server.URL); err != nil {
+		t.Errorf("ParseBinaryFromRemoteFile() error = %v", err)
+	}
+	if http.DefaultTransport.(*http.Transport).TLSClientConfig != defaultTLS {
+		t.Error("WithTLSConfig modified http.DefaultTransport")
+	}
+
+	if _, err := LoadTLSConfig("", certFile, ""); err == nil {
+		t.Error("LoadTLSConfig() without a key succeeded")
+	}
+	if _, err := LoadTLSConfig(keyFile, "", ""); err == nil {
+		t.Error("LoadTLSConfig() with a file without certificates succeeded")
+	}
+}
+
+// writePEM 将DER数据以PEM格式写入文件
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,122 @@
+package gobinaryparser
+
+import (
+	"debug/gosym"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ListPackages 列出Go二进制文件中链接的所有Go包。
+// 与只包含模块信息的构建信息不同，该函数通过pclntab中的函数符号枚举包，
+// 因此可以得到标准库包（例如 net/http、crypto/tls）以及主模块内部的各个包。
+//
+// 参数:
+//   - filePath: Go二进制文件的路径
+//
+// 返回:
+//   - []PackageInfo: 按导入路径排序的包列表
+//   - error: 如果文件无法打开或不包含pclntab，则返回错误信息
+//
+// 使用示例:
+//
+//	packages, err := gobinaryparser.ListPackages("/usr/local/bin/kubectl")
+//	if err != nil {
+//		log.Fatalf("列出包失败: %v", err)
+//	}
+//	for _, pkg := range packages {
+//		if pkg.StdLib {
+//			fmt.Printf("%s (%d个函数)\n", pkg.Path, pkg.Functions)
+//		}
+//	}
+func ListPackages(filePath string) ([]PackageInfo, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开二进制文件失败: %w", err)
+	}
+	defer f.Close()
+
+	return ListPackagesFromReader(f)
+}
+
+// ListPackagesFromReader 从io.ReaderAt接口读取Go二进制文件并列出其中链接的所有Go包。
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - []PackageInfo: 按导入路径排序的包列表
+//   - error: 如果数据不是受支持的可执行文件或不包含pclntab，则返回错误信息
+//
+// 使用示例:
+//
+//	data, _ := os.ReadFile("/usr/local/bin/go")
+//	packages, err := gobinaryparser.ListPackagesFromReader(bytes.NewReader(data))
+//	if err != nil {
+//		log.Fatalf("列出包失败: %v", err)
+//	}
+//	fmt.Printf("包数量: %d\n", len(packages))
+func ListPackagesFromReader(r io.ReaderAt) ([]PackageInfo, error) {
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := exe.symbolTable()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for i := range table.Funcs {
+		if pkg := symbolPackage(table.Funcs[i].Sym); pkg != "" {
+			counts[pkg]++
+		}
+	}
+
+	packages := make([]PackageInfo, 0, len(counts))
+	for path, n := range counts {
+		packages = append(packages, PackageInfo{
+			Path:      path,
+			Functions: n,
+			StdLib:    isStdPackage(path),
+		})
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	return packages, nil
+}
+
+// symbolPackage 返回符号所属包的导入路径，编译器生成的符号返回空字符串。
+// 编译器为某些闭包生成的符号使用空白包名 "_"，它们同样不属于任何包。
+// 链接器会对导入路径最后一个元素中的特殊字符（例如 gopkg.in/yaml.v3 中的点号）进行百分号转义，
+// 这里将其还原为原始导入路径。
+func symbolPackage(sym *gosym.Sym) string {
+	pkg := sym.PackageName()
+	if pkg == "" || pkg == "_" || strings.ContainsAny(pkg, ":") {
+		return ""
+	}
+	if strings.Contains(pkg, "%") {
+		if unescaped, err := url.PathUnescape(pkg); err == nil {
+			pkg = unescaped
+		}
+	}
+	return pkg
+}
+
+// isStdPackage 判断包是否属于标准库，
+// 标准库内部vendor的包（例如 vendor/golang.org/x/net/http2/hpack）也视为标准库。
+// 只检查路径的第一段，因为FIPS模块快照等标准库包的路径中也可能包含点号
+// （例如 crypto/internal/fips140/v1.0.0）。
+func isStdPackage(path string) bool {
+	if path == "main" {
+		return false
+	}
+	first, _, _ := strings.Cut(path, "/")
+	return first == "vendor" || IsStdLib(first)
+}
@@ -0,0 +1,92 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"debug/gosym"
+	"testing"
+)
+
+func TestListPackages(t *testing.T) {
+	packages, err := ListPackages(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("ListPackages failed: %v", err)
+	}
+
+	byPath := make(map[string]PackageInfo)
+	for _, pkg := range packages {
+		byPath[pkg.Path] = pkg
+	}
+
+	// The test binary always links these packages
+	for _, path := range []string{"runtime", "testing", "debug/gosym"} {
+		pkg, ok := byPath[path]
+		if !ok {
+			t.Errorf("Expected package %s to be listed", path)
+			continue
+		}
+		if !pkg.StdLib {
+			t.Errorf("Expected %s to be reported as standard library", path)
+		}
+		if pkg.Functions == 0 {
+			t.Errorf("Expected %s to have functions", path)
+		}
+	}
+
+	const self = "github.com/scagogogo/golang-binary-dependencies-parser/pkg/gobinaryparser"
+	if pkg, ok := byPath[self]; !ok {
+		t.Errorf("Expected package %s to be listed", self)
+	} else if pkg.StdLib {
+		t.Errorf("Expected %s not to be reported as standard library", self)
+	}
+
+	for i := 1; i < len(packages); i++ {
+		if packages[i-1].Path >= packages[i].Path {
+			t.Fatalf("Expected packages sorted by path, got %s before %s", packages[i-1].Path, packages[i].Path)
+		}
+	}
+}
+
+func TestListPackagesFromReader_Invalid(t *testing.T) {
+	_, err := ListPackagesFromReader(bytes.NewReader([]byte("This is not a Go binary")))
+	if err == nil {
+		t.Error("Expected error when listing packages of invalid data, got nil")
+	}
+}
+
+func TestSymbolPackage(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"net/http.(*Client).Do", "net/http"},
+		{"main.main.func1", "main"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml.v3"},
+		{"type:.eq.main.T", ""},
+		{"go:buildid", ""},
+		{"_.goready.func1", ""},
+	}
+
+	for _, tt := range tests {
+		sym := &gosym.Sym{Name: tt.name}
+		if got := symbolPackage(sym); got != tt.want {
+			t.Errorf("symbolPackage(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsStdPackage(t *testing.T) {
+	tests := map[string]bool{
+		"fmt":                            true,
+		"internal/abi":                   true,
+		"vendor/golang.org/x/net/idna":   true,
+		"crypto/internal/fips140/v1.0.0": true,
+		"main":                           false,
+		"github.com/spf13/cobra":         false,
+		"golang.org/x/sys/unix":          false,
+	}
+	for path, want := range tests {
+		if got := isStdPackage(path); got != want {
+			t.Errorf("isStdPackage(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -3,18 +3,26 @@ package gobinaryparser
 
 import (
 	"debug/buildinfo"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"path/filepath"
 )
 
 // ParseBinary 解析指定的Go二进制文件并返回其依赖关系信息。
+// 如果二进制文件缺少构建信息（例如Go 1.13之前编译的二进制文件），会以降级模式
+// 从符号表和pclntab中恢复Go版本和依赖模块，此时返回结果的Degraded字段非nil，
+// 每个依赖都带有可信度标注。
 //
 // 参数:
 //   - filePath: Go二进制文件的路径
 //
 // 返回:
 //   - *BinaryInfo: 包含二进制文件依赖信息的结构体
-//   - error: 如果解析过程中发生错误，则返回错误信息
+//   - error: 如果解析过程中发生错误，则返回 *ParseError，可以用 errors.Is 判断
+//     ErrNotFound、ErrNotGoBinary、ErrNoBuildInfo 或 ErrUnsupportedFormat
 //
 // 使用示例:
 //
@@ -27,23 +35,90 @@ import (
 //	fmt.Printf("Go版本: %s\n", info.GoVersion)
 //	fmt.Printf("依赖数量: %d\n", len(info.Dependencies))
 func ParseBinary(filePath string) (*BinaryInfo, error) {
+	return defaultParser.ParseBinary(filePath)
+}
+
+// ParseBinary 解析指定的Go二进制文件，配置了缓存时以路径、大小和修改时间作为缓存键，参见 ParseBinary
+func (p *Parser) ParseBinary(filePath string) (*BinaryInfo, error) {
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+		return nil, newParseError(filePath, "file", fmt.Errorf("获取绝对路径失败: %w", err))
+	}
+
+	f, err := os.Open(absPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return nil, newParseError(absPath, "file", err)
+	}
+	defer f.Close()
+
+	var key string
+	if stat, err := f.Stat(); err == nil {
+		key = fmt.Sprintf("file:%s:%d:%d", absPath, stat.Size(), stat.ModTime().UnixNano())
+	}
+	if info, ok := p.cached(key); ok {
+		return info, nil
+	}
+
+	result, err := p.readBinaryInfo(f, absPath, "file")
+	if err != nil {
+		return nil, newParseError(absPath, "file", err)
+	}
+	p.store(key, result)
+	return result, nil
+}
+
+// readBinaryInfo 读取二进制文件的构建信息并转换为BinaryInfo，
+// ar静态库（c-archive）从包含构建信息的成员中读取；
+// 缺少构建信息时以降级模式恢复，两者都失败时返回读取构建信息的原始错误，
+// 并根据降级恢复失败的原因附加哨兵错误。
+//
+// 参数:
+//   - r: 二进制文件内容的读取器
+//   - path: 二进制文件的路径或标识符
+//   - sourceType: 源类型标识，可以是"file"、"url"、"bytes"、"reader"或"stream"
+func readBinaryInfo(r io.ReaderAt, path string, sourceType string) (*BinaryInfo, error) {
+	if isArchive(r) {
+		return readArchiveBinaryInfo(r, path, sourceType)
 	}
 
-	info, err := buildinfo.ReadFile(absPath)
+	info, err := buildinfo.Read(r)
 	if err != nil {
-		return nil, fmt.Errorf("读取构建信息失败: %w", err)
+		recovered, rerr := recoverBinaryInfo(r, path, sourceType, err)
+		if rerr != nil {
+			return nil, classifyReadError(err, rerr)
+		}
+		return recovered, nil
+	}
+	return createBinaryInfo(info, r, path, sourceType)
+}
+
+// readBinaryInfo 读取二进制文件的构建信息，启用深度扫描时还会搜索嵌入的Go二进制文件
+func (p *Parser) readBinaryInfo(r io.ReaderAt, path string, sourceType string) (*BinaryInfo, error) {
+	result, err := readBinaryInfo(r, path, sourceType)
+	if err != nil || !p.deepScan {
+		return result, err
 	}
 
-	return createBinaryInfo(info, absPath, "file")
+	size := readerSize(r)
+	if size < 0 {
+		p.logger.Debug("无法确定数据大小，跳过深度扫描", "source", path)
+		return result, nil
+	}
+	result.Nested, err = scanNested(r, size, path, sourceType)
+	if err != nil {
+		return nil, fmt.Errorf("深度扫描失败: %w", err)
+	}
+	return result, nil
 }
 
 // createBinaryInfo 从buildinfo.BuildInfo创建BinaryInfo结构体
 //
 // 参数:
 //   - info: Go标准库中buildinfo.BuildInfo结构体，包含了二进制文件的构建信息
+//   - r: 二进制文件内容的读取器，用于读取格式信息和构建ID，为nil时不收集这些信息
 //   - path: 二进制文件的路径或标识符
 //   - sourceType: 源类型标识，可以是"file"、"url"、"bytes"或"reader"
 //
@@ -53,7 +128,7 @@ func ParseBinary(filePath string) (*BinaryInfo, error) {
 //
 // 该函数是内部函数，用于将标准库的buildinfo.BuildInfo转换为本包定义的BinaryInfo结构体，
 // 并处理所有依赖关系，包括被替换的依赖。
-func createBinaryInfo(info *buildinfo.BuildInfo, path string, sourceType string) (*BinaryInfo, error) {
+func createBinaryInfo(info *buildinfo.BuildInfo, r io.ReaderAt, path string, sourceType string) (*BinaryInfo, error) {
 	// 将构建设置转换为map
 	buildSettings := make(map[string]string)
 	for _, setting := range info.Settings {
@@ -62,14 +137,28 @@ func createBinaryInfo(info *buildinfo.BuildInfo, path string, sourceType string)
 
 	result := &BinaryInfo{
 		Path:          info.Path,
+		modulePath:    info.Main.Path,
 		Version:       info.Main.Version,
 		GoVersion:     info.GoVersion,
 		BuildSettings: buildSettings,
+		BuildConfig:   NewBuildConfig(buildSettings),
 		FilePath:      path,
 		SourceType:    sourceType,
 		Dependencies:  make([]DependencyInfo, 0, len(info.Deps)),
 	}
 
+	// 格式信息和构建ID是对构建信息的补充，读取失败不影响解析结果
+	var exe *executable
+	if r != nil {
+		if opened, err := openExecutable(r); err == nil {
+			exe = opened
+			result.Format, _ = exe.formatInfo()
+			result.BuildID, _ = exe.buildID()
+		}
+		result.RawBuildInfo, _ = readRawBuildInfo(r, exe)
+	}
+	result.BuildMode = resolveBuildMode(buildSettings, exe)
+
 	// 提取依赖信息
 	for _, dep := range info.Deps {
 		depInfo := DependencyInfo{
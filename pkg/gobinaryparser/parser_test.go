@@ -2,6 +2,7 @@ package gobinaryparser
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 )
@@ -126,6 +127,18 @@ func createMockBinaryFile(t *testing.T) string {
 	return tempFile.Name()
 }
 
+// testBinaryPath returns the path of the running test binary, which is a real
+// Go executable with build info and an unstripped pclntab.
+func testBinaryPath(t *testing.T) string {
+	t.Helper()
+
+	path, err := os.Executable()
+	if err != nil {
+		t.Skipf("Cannot locate test binary: %v", err)
+	}
+	return path
+}
+
 // TestBinaryInfo tests the BinaryInfo structure and its methods
 func TestBinaryInfo(t *testing.T) {
 	// Create a test BinaryInfo
@@ -209,3 +222,34 @@ func TestBinaryInfo(t *testing.T) {
 			replacedDeps[0].Path)
 	}
 }
+
+// buildTestProgram compiles a single-file Go program with the given environment
+// and build flags and returns the path of the output file. The test is skipped
+// when the go command is unavailable or the build fails (for example because
+// cgo has no C compiler).
+func buildTestProgram(t *testing.T, src string, env []string, buildArgs ...string) string {
+	t.Helper()
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not available")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/testprog\n\ngo 1.18\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	out := filepath.Join(dir, "out")
+	args := append([]string{"build", "-o", out}, buildArgs...)
+	cmd := exec.Command(goTool, append(args, ".")...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GOFLAGS="), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("go build %v failed: %v\n%s", buildArgs, err, output)
+	}
+	return out
+}
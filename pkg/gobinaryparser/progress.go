@@ -0,0 +1,94 @@
+package gobinaryparser
+
+import (
+	"io"
+	"time"
+)
+
+// progressInterval 是两次进度回调之间的最小间隔
+const progressInterval = 100 * time.Millisecond
+
+// DownloadProgress 表示下载远程文件的进度
+// 示例：
+//
+//	{
+//	  "url": "https://example.com/binaries/kubectl",
+//	  "bytes_read": 52428800,
+//	  "total": 524288000,
+//	  "rate": 10485760,
+//	  "elapsed": 5000000000,
+//	  "done": false
+//	}
+type DownloadProgress struct {
+	URL       string        `json:"url"`        // 下载的URL
+	BytesRead int64         `json:"bytes_read"` // 已下载的字节数
+	Total     int64         `json:"total"`      // 响应的Content-Length，未知时为-1
+	Rate      float64       `json:"rate"`       // 从开始下载以来的平均速度，单位为字节/秒
+	Elapsed   time.Duration `json:"elapsed"`    // 从开始下载以来经过的时间
+	Done      bool          `json:"done"`       // 下载已完成，这是最后一次回调
+}
+
+// Percent 返回下载完成的百分比（0到100），总大小未知时返回-1
+func (p DownloadProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	percent := float64(p.BytesRead) * 100 / float64(p.Total)
+	if percent > 100 {
+		percent = 100
+	}
+	return percent
+}
+
+// ProgressFunc 接收下载进度的回调函数，在第一次读取响应时、下载过程中每隔约100毫秒以及下载完成时调用。
+// 回调在读取响应的goroutine中同步调用，不应长时间阻塞。
+type ProgressFunc func(DownloadProgress)
+
+// progressReader 在读取响应体时报告下载进度
+type progressReader struct {
+	r        io.Reader
+	fn       ProgressFunc
+	progress DownloadProgress
+	start    time.Time
+	last     time.Time
+}
+
+// newProgressReader 创建报告下载进度的读取器，第一次读取时报告下载开始，
+// 因此Content-Length超过大小限制而没有读取的下载不会报告进度
+func newProgressReader(r io.Reader, url string, total int64, fn ProgressFunc) *progressReader {
+	if total < 0 {
+		total = -1
+	}
+	return &progressReader{
+		r:        r,
+		fn:       fn,
+		progress: DownloadProgress{URL: url, Total: total},
+		start:    time.Now(),
+	}
+}
+
+// Read 实现io.Reader接口
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.progress.BytesRead += int64(n)
+	if now := time.Now(); err == io.EOF {
+		r.report(now, true)
+	} else if now.Sub(r.last) >= progressInterval {
+		r.report(now, false)
+	}
+	return n, err
+}
+
+// report 调用进度回调
+func (r *progressReader) report(now time.Time, done bool) {
+	if r.progress.Done {
+		return
+	}
+	r.last = now
+	r.progress.Elapsed = now.Sub(r.start)
+	if seconds := r.progress.Elapsed.Seconds(); seconds > 0 {
+		r.progress.Rate = float64(r.progress.BytesRead) / seconds
+	}
+	r.progress.Done = done
+	r.fn(r.progress)
+}
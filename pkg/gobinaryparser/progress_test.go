@@ -0,0 +1,91 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParser_Progress(t *testing.T) {
+	data := readTestBinary(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	var events []DownloadProgress
+	p := NewParser(WithProgress(func(progress DownloadProgress) {
+		events = append(events, progress)
+	}))
+	if _, err := p.ParseBinaryFromURL(server.URL); err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+
+	if len(events) < 2 {
+		t.Fatalf("Received %d progress events, want at least a start and a final event", len(events))
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].BytesRead < events[i-1].BytesRead {
+			t.Errorf("BytesRead decreased from %d to %d", events[i-1].BytesRead, events[i].BytesRead)
+		}
+	}
+	last := events[len(events)-1]
+	if !last.Done || last.BytesRead != int64(len(data)) || last.Total != int64(len(data)) || last.URL != server.URL {
+		t.Errorf("Final event = %+v, want done with %d bytes", last, len(data))
+	}
+	if last.Percent() != 100 || last.Rate <= 0 {
+		t.Errorf("Percent() = %v, Rate = %v, want 100 and a positive rate", last.Percent(), last.Rate)
+	}
+}
+
+func TestParser_ProgressSizeLimit(t *testing.T) {
+	data := readTestBinary(t)
+	chunked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !chunked {
+			http.ServeContent(w, r, "binary", time.Time{}, bytes.NewReader(data))
+			return
+		}
+		// 不发送Content-Length，只能在读取过程中发现超过限制
+		for i := 0; i < len(data); i += 4096 {
+			w.Write(data[i:min(i+4096, len(data))])
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	var events []DownloadProgress
+	p := NewParser(WithMaxDownloadSize(64<<10), WithProgress(func(progress DownloadProgress) {
+		events = append(events, progress)
+	}))
+	if _, err := p.ParseBinaryFromURL(server.URL); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ParseBinaryFromURL() error = %v, want ErrTooLarge", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Received %d progress events for a download rejected by Content-Length", len(events))
+	}
+
+	chunked = true
+	if _, err := p.ParseBinaryFromURL(server.URL); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ParseBinaryFromURL() error = %v, want ErrTooLarge", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("Received no progress events for a chunked download")
+	}
+	last := events[len(events)-1]
+	if last.Done || last.Total != -1 || last.BytesRead > 128<<10 {
+		t.Errorf("Last event = %+v, want an unfinished download of unknown size aborted near the limit", last)
+	}
+}
+
+func TestDownloadProgress_Percent(t *testing.T) {
+	if got := (DownloadProgress{BytesRead: 25, Total: 100}).Percent(); got != 25 {
+		t.Errorf("Percent() = %v, want 25", got)
+	}
+	if got := (DownloadProgress{BytesRead: 25, Total: -1}).Percent(); got != -1 {
+		t.Errorf("Percent() with unknown total = %v, want -1", got)
+	}
+}
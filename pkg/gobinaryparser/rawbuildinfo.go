@@ -0,0 +1,232 @@
+package gobinaryparser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// 构建信息头部的格式
+const (
+	RawFormatInline  = "inline"  // Go 1.18及以上版本：Go版本和模块信息以uvarint长度前缀的形式紧跟在头部之后
+	RawFormatPointer = "pointer" // Go 1.18之前的版本：头部中保存指向Go字符串头的指针
+)
+
+// RawBuildInfo 表示 debug/buildinfo 读取的原始构建信息，用于取证和归档原始证据。
+// 所有偏移都是相对于被解析文件开头的字节偏移：静态库中的成员、通用二进制文件的切片
+// 和嵌入的二进制文件也按外层文件计算。
+// 示例：
+//
+//	{
+//	  "offset": 4321280,
+//	  "header": "/yBHbyBidWlsZGluZjoIAgAAAAAAAAAAAAAAAAAAAAA=",
+//	  "format": "inline",
+//	  "ptr_size": 8,
+//	  "big_endian": false,
+//	  "go_version": "go1.22.3",
+//	  "go_version_offset": 4321313,
+//	  "mod_info": "MHevDJJ0CAJB4cEH5tYY5nBhdGgJZ2l0aHViLmNvbS9leGFtcGxlL215YXBwCg...",
+//	  "mod_info_offset": 4321323
+//	}
+type RawBuildInfo struct {
+	Offset          int64  `json:"offset"`            // 构建信息头部（"\xff Go buildinf:"）的偏移
+	Header          []byte `json:"header"`            // 头部的32字节原始数据
+	Format          string `json:"format"`            // 头部格式，RawFormatInline 或 RawFormatPointer
+	PtrSize         int    `json:"ptr_size"`          // 头部中记录的指针大小（4或8）
+	BigEndian       bool   `json:"big_endian"`        // 指针格式下指针是否为大端字节序
+	GoVersion       string `json:"go_version"`        // 原始的Go版本字符串
+	GoVersionOffset int64  `json:"go_version_offset"` // Go版本字符串数据的偏移
+	ModInfo         []byte `json:"mod_info"`          // 原始的模块信息，包含前后各16字节的二进制哨兵值，JSON中为base64编码；没有模块信息时为空
+	ModInfoOffset   int64  `json:"mod_info_offset"`   // 模块信息字符串数据的偏移，没有模块信息时为-1
+}
+
+// ReadRawBuildInfo 读取二进制文件中的原始构建信息。
+// 与解析函数返回的 BinaryInfo.RawBuildInfo 相同，但不解码模块信息，
+// 也可以用于模块信息无法被 debug/buildinfo 解析的文件。
+//
+// 参数:
+//   - r: 二进制文件内容的读取器
+//
+// 返回:
+//   - *RawBuildInfo: 原始构建信息
+//   - error: 如果找不到构建信息或构建信息损坏，则返回错误信息
+//
+// 使用示例:
+//
+//	f, _ := os.Open("/usr/local/bin/myapp")
+//	defer f.Close()
+//	raw, err := gobinaryparser.ReadRawBuildInfo(f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("偏移 %d, 格式 %s, sha256 %x\n", raw.Offset, raw.Format, sha256.Sum256(raw.ModInfo))
+func ReadRawBuildInfo(r io.ReaderAt) (*RawBuildInfo, error) {
+	if isArchive(r) {
+		members, err := readArchiveMembers(r)
+		if err != nil {
+			return nil, err
+		}
+		var lastErr error
+		for _, member := range members {
+			sr := io.NewSectionReader(r, member.Offset, member.Size)
+			var raw *RawBuildInfo
+			offset, err := findBuildInfo(sr, member.Size, buildInfoAlign, func(offset int64) bool {
+				var err error
+				if raw, err = parseRawBuildInfo(sr, offset, nil); err != nil {
+					lastErr = fmt.Errorf("静态库成员 %s: %w", member.Name, err)
+					return false
+				}
+				return true
+			})
+			if err != nil || offset < 0 {
+				continue
+			}
+			raw.shift(member.Offset)
+			return raw, nil
+		}
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("%w: 静态库中没有包含Go构建信息的成员", ErrNotGoBinary)
+	}
+
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+	return readRawBuildInfo(r, exe)
+}
+
+// readRawBuildInfo 查找并读取可执行文件的原始构建信息，exe为nil时在整个文件中搜索构建信息头部
+func readRawBuildInfo(r io.ReaderAt, exe *executable) (*RawBuildInfo, error) {
+	offset := int64(-1)
+	if exe != nil {
+		offset = exe.buildInfoOffset()
+	}
+	if offset >= 0 {
+		return parseRawBuildInfo(r, offset, exe)
+	}
+
+	size := readerSize(r)
+	if size < 0 {
+		size = math.MaxInt64
+	}
+	var raw *RawBuildInfo
+	var lastErr error
+	offset, err := findBuildInfo(r, size, buildInfoAlign, func(offset int64) bool {
+		raw, lastErr = parseRawBuildInfo(r, offset, exe)
+		return lastErr == nil
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case offset >= 0:
+		return raw, nil
+	case lastErr != nil:
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%w: 未找到构建信息头部", ErrNoBuildInfo)
+}
+
+// parseRawBuildInfo 读取offset处的构建信息头部及其引用的Go版本和模块信息。
+// 指针格式需要通过可执行文件的段把虚拟地址转换为文件偏移，exe为nil时只支持内联格式。
+func parseRawBuildInfo(r io.ReaderAt, offset int64, exe *executable) (*RawBuildInfo, error) {
+	header := make([]byte, buildInfoHeaderSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, fmt.Errorf("读取构建信息头部失败: %w", err)
+	}
+	if string(header[:len(buildInfoMagic)]) != string(buildInfoMagic) {
+		return nil, fmt.Errorf("构建信息魔数不匹配")
+	}
+
+	flags := header[len(buildInfoMagic)+1]
+	raw := &RawBuildInfo{
+		Offset:        offset,
+		Header:        header,
+		PtrSize:       int(header[len(buildInfoMagic)]),
+		BigEndian:     flags&1 != 0,
+		ModInfoOffset: -1,
+	}
+
+	var modInfo string
+	var err error
+	if flags&2 != 0 {
+		raw.Format = RawFormatInline
+		pos := offset + buildInfoHeaderSize
+		if raw.GoVersion, raw.GoVersionOffset, pos, err = readRawVarintString(r, pos); err != nil {
+			return nil, fmt.Errorf("读取Go版本失败: %w", err)
+		}
+		if modInfo, raw.ModInfoOffset, _, err = readRawVarintString(r, pos); err != nil {
+			return nil, fmt.Errorf("读取模块信息失败: %w", err)
+		}
+	} else {
+		raw.Format = RawFormatPointer
+		if exe == nil {
+			return nil, fmt.Errorf("%w: 指针格式的构建信息需要可执行文件的段信息", ErrNoBuildInfo)
+		}
+		if raw.PtrSize != 4 && raw.PtrSize != 8 {
+			return nil, fmt.Errorf("构建信息中的指针大小 %d 无效", raw.PtrSize)
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		if raw.BigEndian {
+			order = binary.BigEndian
+		}
+		ptrs := header[len(buildInfoMagic)+2:]
+		readPtr := func(b []byte) uint64 {
+			if raw.PtrSize == 8 {
+				return order.Uint64(b)
+			}
+			return uint64(order.Uint32(b))
+		}
+		if raw.GoVersion, raw.GoVersionOffset, err = exe.readGoStringAt(readPtr(ptrs)); err != nil {
+			return nil, fmt.Errorf("读取Go版本失败: %w", err)
+		}
+		if modInfo, raw.ModInfoOffset, err = exe.readGoStringAt(readPtr(ptrs[raw.PtrSize:])); err != nil {
+			return nil, fmt.Errorf("读取模块信息失败: %w", err)
+		}
+	}
+
+	if modInfo == "" {
+		raw.ModInfoOffset = -1
+	} else {
+		raw.ModInfo = []byte(modInfo)
+	}
+	return raw, nil
+}
+
+// readRawVarintString 读取pos处以uvarint长度为前缀的字符串，返回字符串、字符串数据的偏移和字符串之后的偏移
+func readRawVarintString(r io.ReaderAt, pos int64) (s string, dataOffset int64, next int64, err error) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n, err := r.ReadAt(buf, pos)
+	if n == 0 {
+		return "", 0, 0, err
+	}
+	length, size := binary.Uvarint(buf[:n])
+	if size <= 0 {
+		return "", 0, 0, fmt.Errorf("长度前缀无效")
+	}
+	if length > 64<<20 {
+		return "", 0, 0, fmt.Errorf("字符串长度 %d 无效", length)
+	}
+	dataOffset = pos + int64(size)
+	data := make([]byte, length)
+	if _, err := r.ReadAt(data, dataOffset); err != nil {
+		return "", 0, 0, err
+	}
+	return string(data), dataOffset, dataOffset + int64(length), nil
+}
+
+// shift 将所有偏移加上base，用于把相对于静态库成员、通用二进制文件切片或嵌入二进制文件的偏移转换为相对于外层文件的偏移
+func (raw *RawBuildInfo) shift(base int64) {
+	if raw == nil {
+		return
+	}
+	raw.Offset += base
+	if raw.GoVersionOffset >= 0 {
+		raw.GoVersionOffset += base
+	}
+	if raw.ModInfoOffset >= 0 {
+		raw.ModInfoOffset += base
+	}
+}
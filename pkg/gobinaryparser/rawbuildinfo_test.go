@@ -0,0 +1,163 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"testing"
+)
+
+// checkRawBuildInfo 检查原始构建信息中的字符串与数据中对应偏移处的字节一致
+func checkRawBuildInfo(t *testing.T, data []byte, raw *RawBuildInfo) {
+	t.Helper()
+	if raw == nil {
+		t.Fatal("Expected raw build info")
+	}
+	if !bytes.Equal(data[raw.Offset:raw.Offset+buildInfoHeaderSize], raw.Header) {
+		t.Errorf("Header does not match the bytes at offset %d", raw.Offset)
+	}
+	if !bytes.HasPrefix(raw.Header, buildInfoMagic) {
+		t.Errorf("Header = %q, want prefix %q", raw.Header, buildInfoMagic)
+	}
+	if raw.Format != RawFormatInline {
+		t.Errorf("Format = %q, want %q", raw.Format, RawFormatInline)
+	}
+	if raw.PtrSize != 4 && raw.PtrSize != 8 {
+		t.Errorf("PtrSize = %d, want 4 or 8", raw.PtrSize)
+	}
+	if raw.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", raw.GoVersion, runtime.Version())
+	}
+	if got := string(data[raw.GoVersionOffset : raw.GoVersionOffset+int64(len(raw.GoVersion))]); got != raw.GoVersion {
+		t.Errorf("Bytes at GoVersionOffset = %q, want %q", got, raw.GoVersion)
+	}
+	if got := data[raw.ModInfoOffset : raw.ModInfoOffset+int64(len(raw.ModInfo))]; !bytes.Equal(got, raw.ModInfo) {
+		t.Errorf("Bytes at ModInfoOffset do not match ModInfo")
+	}
+	// 模块信息前后各有16字节的哨兵值
+	if len(raw.ModInfo) < 32 || !bytes.Contains(raw.ModInfo[16:], []byte("path\t")) {
+		t.Errorf("ModInfo = %q, want sentinel-wrapped module info", raw.ModInfo)
+	}
+
+	// JSON中的模块信息必须保留哨兵值的原始字节
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded RawBuildInfo
+	if err := json.Unmarshal(encoded, &decoded); err != nil || !bytes.Equal(decoded.ModInfo, raw.ModInfo) {
+		t.Errorf("ModInfo does not survive a JSON round trip: %v", err)
+	}
+}
+
+func TestReadRawBuildInfo(t *testing.T) {
+	data := readTestBinary(t)
+
+	raw, err := ReadRawBuildInfo(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadRawBuildInfo() error = %v", err)
+	}
+	checkRawBuildInfo(t, data, raw)
+
+	if _, err := ReadRawBuildInfo(bytes.NewReader([]byte("not a binary"))); err == nil {
+		t.Error("Expected error for a file that is not a binary")
+	}
+}
+
+func TestParseBinary_RawBuildInfo(t *testing.T) {
+	data := readTestBinary(t)
+
+	t.Run("file", func(t *testing.T) {
+		info, err := ParseBinaryFromFile(testBinaryPath(t))
+		if err != nil {
+			t.Fatalf("ParseBinaryFromFile() error = %v", err)
+		}
+		checkRawBuildInfo(t, data, info.RawBuildInfo)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		info, err := ParseBinaryFromBytes(data)
+		if err != nil {
+			t.Fatalf("ParseBinaryFromBytes() error = %v", err)
+		}
+		checkRawBuildInfo(t, data, info.RawBuildInfo)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		info, err := ParseBinaryFromStream(context.Background(), bytes.NewReader(data), &StreamOptions{TempDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("ParseBinaryFromStream() error = %v", err)
+		}
+		checkRawBuildInfo(t, data, info.RawBuildInfo)
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		archive := buildGNUArchive([]arMember{
+			{"000000.o", []byte("not a Go object")},
+			{"go.o", data},
+		})
+		info, err := ParseBinaryFromBytes(archive)
+		if err != nil {
+			t.Fatalf("ParseBinaryFromBytes() error = %v", err)
+		}
+		// 偏移按整个静态库计算
+		checkRawBuildInfo(t, archive, info.RawBuildInfo)
+
+		raw, err := ReadRawBuildInfo(bytes.NewReader(archive))
+		if err != nil {
+			t.Fatalf("ReadRawBuildInfo() error = %v", err)
+		}
+		checkRawBuildInfo(t, archive, raw)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		combined, _ := embedBinary(data, data)
+		info, err := NewParser(WithDeepScan(true)).ParseBinaryFromReader(bytes.NewReader(combined))
+		if err != nil {
+			t.Fatalf("ParseBinaryFromReader() error = %v", err)
+		}
+		if len(info.Nested) != 1 {
+			t.Fatalf("Expected 1 nested binary, got %d", len(info.Nested))
+		}
+		nested := info.Nested[0]
+		checkRawBuildInfo(t, combined, nested.Info.RawBuildInfo)
+		if nested.Info.RawBuildInfo.Offset != nested.BuildInfoOffset {
+			t.Errorf("RawBuildInfo.Offset = %d, want %d", nested.Info.RawBuildInfo.Offset, nested.BuildInfoOffset)
+		}
+	})
+}
+
+func TestParseRawBuildInfo_PointerFormatWithoutExecutable(t *testing.T) {
+	header := make([]byte, buildInfoHeaderSize)
+	copy(header, buildInfoMagic)
+	header[len(buildInfoMagic)] = 8
+	if _, err := parseRawBuildInfo(bytes.NewReader(header), 0, nil); err == nil {
+		t.Error("Expected error for the pointer format without executable")
+	}
+}
+
+func TestRawBuildInfo_Shift(t *testing.T) {
+	raw := &RawBuildInfo{Offset: 10, GoVersionOffset: 43, ModInfoOffset: -1}
+	raw.shift(100)
+	if raw.Offset != 110 || raw.GoVersionOffset != 143 || raw.ModInfoOffset != -1 {
+		t.Errorf("shift(100) = %+v", raw)
+	}
+
+	var nilRaw *RawBuildInfo
+	nilRaw.shift(100)
+}
+
+func TestParseBinary_RawBuildInfoDegraded(t *testing.T) {
+	data, _ := corruptedTestBinary(t)
+	info, err := ParseBinaryFromBytes(data)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromBytes() error = %v, want degraded result", err)
+	}
+	if info.Degraded == nil {
+		t.Fatal("Expected Degraded to be set")
+	}
+	if info.RawBuildInfo != nil {
+		t.Errorf("Expected no raw build info in degraded mode, got %+v", info.RawBuildInfo)
+	}
+}
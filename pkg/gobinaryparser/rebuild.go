@@ -0,0 +1,275 @@
+package gobinaryparser
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RebuildIssueKind 表示妨碍精确重现构建的问题类型
+type RebuildIssueKind string
+
+const (
+	RebuildIssueDirtyVCS           RebuildIssueKind = "dirty-vcs"         // 从有未提交修改的工作区构建
+	RebuildIssueMissingRevision    RebuildIssueKind = "missing-revision"  // 没有记录版本控制修订号，无法确定源码版本
+	RebuildIssueLocalReplace       RebuildIssueKind = "local-replace"     // 依赖被替换为本地目录，目录内容没有记录
+	RebuildIssueNoBuildInfo        RebuildIssueKind = "no-build-info"     // 缺少构建信息，构建设置未知
+	RebuildIssueUnknownToolchain   RebuildIssueKind = "unknown-toolchain" // 使用开发版本或无法识别的Go工具链构建
+	RebuildIssueCommandLineSources RebuildIssueKind = "command-line"      // 通过 go build file.go 从文件列表构建
+	RebuildIssueCgo                RebuildIssueKind = "cgo"               // 启用了cgo，结果还依赖C工具链和系统库
+	RebuildIssueNoTrimPath         RebuildIssueKind = "no-trimpath"       // 没有使用 -trimpath，二进制文件包含源码的绝对路径
+	RebuildIssuePGOProfile         RebuildIssueKind = "pgo-profile"       // 使用了PGO配置文件，需要同一个配置文件
+)
+
+// RebuildIssue 描述一个妨碍精确重现构建的问题
+type RebuildIssue struct {
+	Kind    RebuildIssueKind `json:"kind"`    // 问题类型
+	Message string           `json:"message"` // 问题描述
+}
+
+// RebuildPlan 表示根据构建信息重建的 go build 调用
+// 示例：
+//
+//	{
+//	  "toolchain": "go1.22.3",
+//	  "vcs": {"system": "git", "revision": "a7f686d8f418...", ...},
+//	  "env": ["CGO_ENABLED=0", "GOOS=linux", "GOARCH=amd64", "GOAMD64=v3"],
+//	  "args": ["build", "-trimpath", "-ldflags=-s -w", "-o", "myapp", "github.com/example/myapp/cmd/myapp"],
+//	  "issues": [{"kind": "dirty-vcs", "message": "..."}]
+//	}
+type RebuildPlan struct {
+	Toolchain string         `json:"toolchain,omitempty"` // 构建使用的Go工具链，例如 "go1.22.3"，无法识别时为空
+	VCS       *VCSInfo       `json:"vcs,omitempty"`       // 需要检出的版本控制修订，未记录时为nil
+	Env       []string       `json:"env"`                 // 构建时的环境变量，格式为 KEY=VALUE
+	Args      []string       `json:"args"`                // go命令的参数，例如 ["build", "-trimpath", ...]
+	Issues    []RebuildIssue `json:"issues,omitempty"`    // 妨碍精确重现构建的问题
+}
+
+// rebuildFlagKeys 是会影响构建结果的 go build 标志，按 go build 记录的顺序排列
+var rebuildFlagKeys = []string{
+	"-buildmode", "-compiler", "-gccgoflags", "-gcflags", "-asmflags", "-ldflags",
+	"-tags", "-trimpath", "-race", "-msan", "-asan", "-pgo",
+}
+
+// rebuildEnvKeys 是会影响构建结果的环境变量
+var rebuildEnvKeys = append(append([]string{"CGO_ENABLED"}, cgoFlagKeys...),
+	append([]string{"GOOS", "GOARCH", "GOEXPERIMENT"}, archFeatureKeys...)...)
+
+// RebuildPlan 根据构建设置重建生成该二进制文件的 go build 调用。
+// 二进制文件从带版本的模块安装（go install path@version）且没有版本控制信息时生成 go install 调用；
+// 否则生成在检出的源码中执行的 go build 调用。
+// 妨碍精确重现的设置（未提交的修改、本地替换、缺少修订号等）记录在 Issues 中。
+//
+// 返回:
+//   - *RebuildPlan: 重建的构建调用
+//
+// 使用示例:
+//
+//	plan := info.RebuildPlan()
+//	for _, issue := range plan.Issues {
+//		fmt.Printf("警告: %s\n", issue.Message)
+//	}
+//	fmt.Println(plan.Command())
+func (info *BinaryInfo) RebuildPlan() *RebuildPlan {
+	plan := &RebuildPlan{}
+	settings := info.BuildSettings
+	config := info.BuildConfig
+	if config == nil {
+		config = NewBuildConfig(settings)
+	}
+
+	if info.Degraded != nil {
+		plan.addIssue(RebuildIssueNoBuildInfo, "二进制文件缺少构建信息，构建设置未知")
+	}
+
+	if v, err := ParseGoVersion(info.GoVersion); err != nil || v.Devel {
+		plan.addIssue(RebuildIssueUnknownToolchain, fmt.Sprintf("无法确定构建使用的Go工具链 %q", info.GoVersion))
+	} else {
+		plan.Toolchain = strings.Fields(info.GoVersion)[0]
+		if v.Compare(GoVersion{Major: 1, Minor: 21}) >= 0 {
+			plan.Env = append(plan.Env, "GOTOOLCHAIN="+plan.Toolchain)
+		}
+	}
+
+	for _, key := range rebuildEnvKeys {
+		if value, ok := settings[key]; ok {
+			plan.Env = append(plan.Env, key+"="+value)
+		}
+	}
+
+	// go install path@version 安装的二进制文件可以直接从模块代理重新安装
+	install := config.VCS == nil && info.Path != "" && IsValidVersion(info.Version)
+	if install {
+		plan.Args = append(plan.Args, "install")
+	} else {
+		plan.Args = append(plan.Args, "build")
+	}
+	for _, key := range rebuildFlagKeys {
+		value, ok := settings[key]
+		switch {
+		case !ok || value == "" || value == "false":
+		case value == "true":
+			plan.Args = append(plan.Args, key)
+		default:
+			plan.Args = append(plan.Args, key+"="+value)
+		}
+	}
+
+	switch {
+	case install:
+		plan.Args = append(plan.Args, info.Path+"@"+info.Version)
+	case info.Path == "command-line-arguments":
+		plan.addIssue(RebuildIssueCommandLineSources, "二进制文件是通过 go build file.go 从文件列表构建的，构建信息中没有源文件列表")
+		plan.Args = append(plan.Args, "-o", rebuildOutputName(info), ".")
+	default:
+		pkg := info.Path
+		if pkg == "" {
+			pkg = "."
+		}
+		plan.Args = append(plan.Args, "-o", rebuildOutputName(info), pkg)
+	}
+
+	if !install {
+		switch {
+		case config.VCS == nil || config.VCS.Revision == "":
+			plan.addIssue(RebuildIssueMissingRevision, "构建信息中没有版本控制修订号，无法确定源码版本")
+		default:
+			plan.VCS = config.VCS
+			if config.VCS.Modified {
+				plan.addIssue(RebuildIssueDirtyVCS, fmt.Sprintf("二进制文件是从修订 %s 有未提交修改的工作区构建的", config.VCS.Revision))
+			}
+		}
+	}
+
+	for _, dep := range info.Dependencies {
+		if dep.ReplaceKind() == ReplaceLocal {
+			plan.addIssue(RebuildIssueLocalReplace, fmt.Sprintf("依赖 %s 被替换为本地目录 %s", dep.Path, dep.Replace.Path))
+		}
+	}
+	if config.CGOEnabled {
+		plan.addIssue(RebuildIssueCgo, "启用了cgo，构建结果还依赖C编译器和系统库的版本")
+	}
+	if !config.TrimPath && info.Degraded == nil {
+		plan.addIssue(RebuildIssueNoTrimPath, "没有使用 -trimpath，需要在与原始构建相同的目录中构建")
+	}
+	if config.PGO != "" && config.PGO != "off" {
+		plan.addIssue(RebuildIssuePGOProfile, fmt.Sprintf("使用了PGO配置文件 %s，需要同一个配置文件", config.PGO))
+	}
+
+	return plan
+}
+
+// addIssue 添加一个妨碍精确重现构建的问题
+func (p *RebuildPlan) addIssue(kind RebuildIssueKind, message string) {
+	p.Issues = append(p.Issues, RebuildIssue{Kind: kind, Message: message})
+}
+
+// Reproducible 判断是否没有发现妨碍精确重现构建的问题
+func (p *RebuildPlan) Reproducible() bool {
+	return len(p.Issues) == 0
+}
+
+// HasIssue 判断是否存在指定类型的问题
+func (p *RebuildPlan) HasIssue(kind RebuildIssueKind) bool {
+	for _, issue := range p.Issues {
+		if issue.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Command 返回单行的shell命令，例如 "CGO_ENABLED=0 GOOS=linux go build -trimpath -o myapp ./cmd/myapp"
+//
+// 返回:
+//   - string: 参数已按shell规则加上引号的命令
+func (p *RebuildPlan) Command() string {
+	var parts []string
+	for _, env := range p.Env {
+		parts = append(parts, shellQuote(env))
+	}
+	parts = append(parts, "go")
+	for _, arg := range p.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Script 返回重建二进制文件的shell脚本：检出源码修订、设置环境变量并执行go命令，
+// 妨碍精确重现构建的问题以注释的形式列在脚本开头。
+//
+// 返回:
+//   - string: shell脚本
+//
+// 使用示例:
+//
+//	os.WriteFile("rebuild.sh", []byte(info.RebuildPlan().Script()), 0o755)
+func (p *RebuildPlan) Script() string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	if p.Toolchain != "" {
+		fmt.Fprintf(&b, "# Built with %s\n", p.Toolchain)
+	}
+	if len(p.Issues) > 0 {
+		b.WriteString("#\n# WARNING: the build cannot be reproduced exactly:\n")
+		for _, issue := range p.Issues {
+			fmt.Fprintf(&b, "#   - [%s] %s\n", issue.Kind, issue.Message)
+		}
+	}
+	b.WriteString("set -e\n\n")
+
+	if p.VCS != nil {
+		switch p.VCS.System {
+		case "git":
+			fmt.Fprintf(&b, "git checkout %s\n", shellQuote(p.VCS.Revision))
+		case "hg":
+			fmt.Fprintf(&b, "hg update -r %s\n", shellQuote(p.VCS.Revision))
+		default:
+			fmt.Fprintf(&b, "# check out %s revision %s\n", p.VCS.System, p.VCS.Revision)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, env := range p.Env {
+		fmt.Fprintf(&b, "export %s\n", shellQuote(env))
+	}
+	if len(p.Env) > 0 {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("go")
+	for _, arg := range p.Args {
+		b.WriteString(" " + shellQuote(arg))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// rebuildOutputName 返回重建的二进制文件名，优先使用原始文件名
+func rebuildOutputName(info *BinaryInfo) string {
+	if info.FilePath != "" && (info.SourceType == "file" || info.SourceType == "url") {
+		return path.Base(strings.ReplaceAll(info.FilePath, "\\", "/"))
+	}
+	if info.Path != "" && info.Path != "command-line-arguments" {
+		return path.Base(info.Path)
+	}
+	return "a.out"
+}
+
+// shellQuote 在参数包含shell特殊字符时用单引号包围参数
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@,+%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
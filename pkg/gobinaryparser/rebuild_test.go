@@ -0,0 +1,162 @@
+package gobinaryparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBinaryInfo_RebuildPlan(t *testing.T) {
+	info := &BinaryInfo{
+		Path:       "github.com/example/app/cmd/app",
+		modulePath: "github.com/example/app",
+		Version:    "(devel)",
+		GoVersion:  "go1.22.3",
+		FilePath:   "/usr/local/bin/app",
+		SourceType: "file",
+		BuildSettings: map[string]string{
+			"-buildmode":   "exe",
+			"-compiler":    "gc",
+			"-ldflags":     `-s -w -X "main.version=1.0 beta"`,
+			"-tags":        "netgo,osusergo",
+			"-trimpath":    "true",
+			"CGO_ENABLED":  "0",
+			"GOARCH":       "amd64",
+			"GOOS":         "linux",
+			"GOAMD64":      "v3",
+			"vcs":          "git",
+			"vcs.revision": "a7f686d8f418",
+			"vcs.modified": "false",
+		},
+	}
+
+	plan := info.RebuildPlan()
+	if !plan.Reproducible() {
+		t.Errorf("Expected reproducible plan, got issues %+v", plan.Issues)
+	}
+	wantEnv := []string{"GOTOOLCHAIN=go1.22.3", "CGO_ENABLED=0", "GOOS=linux", "GOARCH=amd64", "GOAMD64=v3"}
+	if !reflect.DeepEqual(plan.Env, wantEnv) {
+		t.Errorf("Env = %v, want %v", plan.Env, wantEnv)
+	}
+	wantArgs := []string{"build", "-buildmode=exe", "-compiler=gc", `-ldflags=-s -w -X "main.version=1.0 beta"`,
+		"-tags=netgo,osusergo", "-trimpath", "-o", "app", "github.com/example/app/cmd/app"}
+	if !reflect.DeepEqual(plan.Args, wantArgs) {
+		t.Errorf("Args = %v, want %v", plan.Args, wantArgs)
+	}
+
+	script := plan.Script()
+	for _, want := range []string{
+		"git checkout a7f686d8f418\n",
+		"export GOAMD64=v3\n",
+		`go build -buildmode=exe -compiler=gc '-ldflags=-s -w -X "main.version=1.0 beta"' -tags=netgo,osusergo -trimpath -o app github.com/example/app/cmd/app`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Script() missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "WARNING") {
+		t.Errorf("Unexpected warnings in script:\n%s", script)
+	}
+}
+
+func TestBinaryInfo_RebuildPlan_Issues(t *testing.T) {
+	info := &BinaryInfo{
+		Path:      "github.com/example/app",
+		Version:   "(devel)",
+		GoVersion: "devel go1.23-abc123",
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../b", Version: "(devel)"}},
+		},
+		BuildSettings: map[string]string{
+			"CGO_ENABLED":  "1",
+			"-pgo":         "/src/default.pgo",
+			"vcs":          "git",
+			"vcs.revision": "a7f686d8f418",
+			"vcs.modified": "true",
+		},
+	}
+
+	plan := info.RebuildPlan()
+	for _, kind := range []RebuildIssueKind{
+		RebuildIssueUnknownToolchain, RebuildIssueDirtyVCS, RebuildIssueLocalReplace,
+		RebuildIssueCgo, RebuildIssueNoTrimPath, RebuildIssuePGOProfile,
+	} {
+		if !plan.HasIssue(kind) {
+			t.Errorf("Expected issue %q, got %+v", kind, plan.Issues)
+		}
+	}
+	if plan.HasIssue(RebuildIssueMissingRevision) || plan.Toolchain != "" {
+		t.Errorf("Unexpected plan: %+v", plan)
+	}
+
+	delete(info.BuildSettings, "vcs.revision")
+	if plan := info.RebuildPlan(); !plan.HasIssue(RebuildIssueMissingRevision) || plan.VCS != nil {
+		t.Errorf("Expected missing revision, got %+v", plan)
+	}
+}
+
+func TestBinaryInfo_RebuildPlan_LocalReplace(t *testing.T) {
+	// 构建信息中本地目录替换记录为 "=> ../b (devel)"
+	info := &BinaryInfo{
+		Path:      "github.com/example/app",
+		Version:   "(devel)",
+		GoVersion: "go1.22.3",
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../b", Version: "(devel)"}},
+			{Path: "github.com/c/d", Version: "v1.2.0", Replace: &DependencyInfo{Path: "github.com/fork/d", Version: "v1.2.1", Sum: "h1:fork"}},
+		},
+		BuildSettings: map[string]string{"-trimpath": "true", "CGO_ENABLED": "0"},
+	}
+
+	plan := info.RebuildPlan()
+	var local []RebuildIssue
+	for _, issue := range plan.Issues {
+		if issue.Kind == RebuildIssueLocalReplace {
+			local = append(local, issue)
+		}
+	}
+	if len(local) != 1 || !strings.Contains(local[0].Message, "../b") {
+		t.Errorf("Local replace issues = %+v, want one issue for ../b", local)
+	}
+	if script := plan.Script(); !strings.Contains(script, "WARNING") {
+		t.Errorf("Script() does not warn about the local replace:\n%s", script)
+	}
+}
+
+func TestBinaryInfo_RebuildPlan_Install(t *testing.T) {
+	info := &BinaryInfo{
+		Path:      "golang.org/x/tools/cmd/stringer",
+		Version:   "v0.20.0",
+		GoVersion: "go1.20.14",
+		BuildSettings: map[string]string{
+			"-trimpath":   "true",
+			"CGO_ENABLED": "0",
+		},
+	}
+
+	plan := info.RebuildPlan()
+	if got, want := plan.Command(), "CGO_ENABLED=0 go install -trimpath golang.org/x/tools/cmd/stringer@v0.20.0"; got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	if !plan.Reproducible() {
+		t.Errorf("Unexpected issues: %+v", plan.Issues)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":                 "''",
+		"-trimpath":        "-trimpath",
+		"GOOS=linux":       "GOOS=linux",
+		"-ldflags=-s -w":   "'-ldflags=-s -w'",
+		"it's":             `'it'\''s'`,
+		"-tags=a,b":        "-tags=a,b",
+		"$HOME":            "'$HOME'",
+		"cmd/...=-dwarf=0": "cmd/...=-dwarf=0",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package gobinaryparser
+
+import (
+	"debug/gosym"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// recoverBinaryInfo 在二进制文件缺少构建信息时以降级模式恢复信息。
+// Go版本从 runtime.buildVersion 符号读取，符号表被剥离时扫描只读数据；
+// 依赖模块从pclntab中记录的源文件路径和函数所属的包推断，并标注可信度。
+//
+// 参数:
+//   - r: 二进制文件内容的读取器
+//   - path: 二进制文件的路径或标识符
+//   - sourceType: 源类型标识
+//   - cause: 读取构建信息失败的原因
+//
+// 返回:
+//   - *BinaryInfo: 恢复出的信息，Degraded字段非nil
+//   - error: 如果文件不是可识别的Go二进制文件（没有pclntab），则返回错误信息
+func recoverBinaryInfo(r io.ReaderAt, path string, sourceType string, cause error) (*BinaryInfo, error) {
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+	table, err := exe.symbolTable()
+	if err != nil {
+		return nil, err
+	}
+	// pclntab头部损坏时gosym不会报错，只会得到空的符号表
+	if len(table.Funcs) == 0 {
+		return nil, fmt.Errorf("%w: pclntab中没有函数", ErrNoBuildInfo)
+	}
+
+	result := &BinaryInfo{
+		BuildSettings: make(map[string]string),
+		BuildConfig:   NewBuildConfig(nil),
+		FilePath:      path,
+		SourceType:    sourceType,
+		Degraded:      &DegradedInfo{Reason: cause.Error()},
+	}
+	result.Format, _ = exe.formatInfo()
+	result.BuildID, _ = exe.buildID()
+	result.BuildMode = exe.buildMode()
+
+	version, source, confidence := exe.recoverGoVersion()
+	result.GoVersion = version
+	result.Degraded.GoVersionSource = source
+	result.Degraded.GoVersionConfidence = confidence
+
+	inferred := inferModules(table)
+	result.Path = inferred.mainPackage
+	result.modulePath = inferred.mainModule
+	result.Version = inferred.mainVersion
+	result.Dependencies = inferred.deps
+
+	return result, nil
+}
+
+// recoverGoVersion 恢复编译二进制文件使用的Go版本，返回版本、来源和可信度
+func (e *executable) recoverGoVersion() (version, source string, confidence Confidence) {
+	if sym, err := e.lookupSymbol("runtime.buildVersion"); err == nil {
+		if v, err := e.readGoString(sym.Addr); err == nil && isGoVersionString(v) {
+			return v, "symbol", ConfidenceHigh
+		}
+	}
+	if v := e.scanGoVersion(); v != "" {
+		return v, "scan", ConfidenceLow
+	}
+	return "", "", ""
+}
+
+// isGoVersionString 判断字符串是否像 runtime.Version() 的返回值
+func isGoVersionString(v string) bool {
+	return strings.HasPrefix(v, "go1") || strings.HasPrefix(v, "devel ")
+}
+
+// goVersionPattern 匹配只读数据中的Go版本字符串，例如 go1.11.13、go1.12rc1
+var goVersionPattern = regexp.MustCompile(`go1\.(\d{1,2})(?:\.(\d{1,2}))?(?:(beta|rc)(\d{1,2}))?`)
+
+// scanChunkSize 是扫描只读数据或深度扫描整个文件时每次读取的字节数
+const scanChunkSize = 1 << 20
+
+// scanGoVersion 在只读数据段中搜索Go版本字符串，返回找到的最高版本。
+// Go字符串在只读数据中首尾相接地存放，没有分隔符，因此这种方式只能作为最后的手段。
+func (e *executable) scanGoVersion() string {
+	var best GoVersion
+	for _, sect := range e.sections() {
+		if sect.Kind != symRodata {
+			continue
+		}
+		// 相邻的块之间保留少量重叠，避免版本字符串被截断
+		for addr := sect.Start; addr < sect.End; addr += scanChunkSize - 16 {
+			size := sect.End - addr
+			if size > scanChunkSize {
+				size = scanChunkSize
+			}
+			data, err := e.readVirtual(addr, int(size))
+			if err != nil {
+				break
+			}
+			for _, m := range goVersionPattern.FindAll(data, -1) {
+				v, err := ParseGoVersion(string(m))
+				if err == nil && (best.Raw == "" || best.Less(v)) {
+					best = v
+				}
+			}
+			if size < scanChunkSize {
+				break
+			}
+		}
+	}
+	return best.Raw
+}
+
+// inferredModules 是从pclntab推断出的模块信息
+type inferredModules struct {
+	mainPackage string
+	mainModule  string
+	mainVersion string
+	deps        []DependencyInfo
+}
+
+// inferModules 从pclntab的源文件路径和函数所属的包推断主模块和依赖模块。
+//   - 模块缓存中的路径（.../pkg/mod/github.com/x/y@v1.2.3/...）以及使用 -trimpath
+//     编译时的路径（github.com/x/y@v1.2.3/...）直接给出模块路径和版本，可信度为高
+//   - vendor目录中的包确认被编译进了二进制文件，但模块根路径是推测的，可信度为中
+//   - 其余非标准库的包（例如在GOPATH模式下编译的依赖）按导入路径推测模块根路径，可信度为低
+func inferModules(table *gosym.Table) inferredModules {
+	var result inferredModules
+	modules := make(map[string]*DependencyInfo)
+	add := func(modPath, version string, confidence Confidence) {
+		if dep, ok := modules[modPath]; ok {
+			if confidenceRank(confidence) > confidenceRank(dep.Confidence) {
+				dep.Version, dep.Confidence = version, confidence
+			}
+			return
+		}
+		modules[modPath] = &DependencyInfo{Path: modPath, Version: version, Confidence: confidence}
+	}
+
+	gorootSrc := ""
+	if fn := table.LookupFunc("runtime.main"); fn != nil {
+		if file, _, _ := table.PCToLine(fn.Entry); strings.HasSuffix(file, "/runtime/proc.go") {
+			gorootSrc = strings.TrimSuffix(file, "runtime/proc.go")
+		}
+	}
+
+	// 每个包取一个源文件，用于推断源码目录和导入路径的对应关系；
+	// 编译器生成的包装函数的源文件为 <autogenerated>，需要跳过
+	pkgFiles := make(map[string]string)
+	for i := range table.Funcs {
+		fn := &table.Funcs[i]
+		pkg := symbolPackage(fn.Sym)
+		if pkg == "" || pkgFiles[pkg] != "" {
+			continue
+		}
+		if file, _, _ := table.PCToLine(fn.Entry); file != "" && !strings.HasPrefix(file, "<") {
+			pkgFiles[pkg] = file
+		}
+	}
+	pkgs := make([]string, 0, len(pkgFiles))
+	for pkg := range pkgFiles {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	// 主包：根据 main.main 所在的源文件推断
+	if file := pkgFiles["main"]; file != "" {
+		dir := path.Dir(file)
+		if modPath, version, rest, ok := parseModuleCachePath(file); ok {
+			// 通过 go install pkg@version 安装的二进制文件，主模块位于模块缓存中
+			result.mainModule, result.mainVersion = modPath, version
+			result.mainPackage = path.Join(modPath, path.Dir(rest))
+		} else if i := strings.LastIndex(dir, "/src/"); i >= 0 {
+			// GOPATH模式：$GOPATH/src/<导入路径>
+			result.mainPackage = dir[i+len("/src/"):]
+			result.mainModule = guessModuleRoot(result.mainPackage)
+		} else if !isAbsPath(dir) {
+			// 使用 -trimpath 编译时，主模块的文件路径以模块路径开头
+			if dir != "." {
+				result.mainPackage = dir
+				result.mainModule = guessModuleRoot(dir)
+			}
+		} else {
+			// 在模块目录中编译：借助主模块中其他包的源文件目录推断导入路径，
+			// 例如 /src/app/pkg/util 对应 github.com/x/app/pkg/util，则 /src/app 对应 github.com/x/app
+			for _, pkg := range pkgs {
+				if pkg == "main" || isStdPackage(pkg) || strings.Contains(pkg, "/vendor/") {
+					continue
+				}
+				root, importRoot, ok := splitSourceRoot(path.Dir(pkgFiles[pkg]), pkg)
+				if !ok || (dir != root && !strings.HasPrefix(dir, root+"/")) {
+					continue
+				}
+				result.mainModule = importRoot
+				result.mainPackage = importRoot + strings.TrimPrefix(dir, root)
+				break
+			}
+		}
+	}
+
+	// 源文件路径：模块缓存和vendor目录
+	for file := range table.Files {
+		if gorootSrc != "" && strings.HasPrefix(file, gorootSrc) {
+			continue
+		}
+		if modPath, version, _, ok := parseModuleCachePath(file); ok {
+			if modPath != result.mainModule {
+				add(modPath, version, ConfidenceHigh)
+			}
+			continue
+		}
+		if i := strings.LastIndex(file, "/vendor/"); i >= 0 {
+			if pkg := path.Dir(file[i+len("/vendor/"):]); pkg != "." && !isStdPackage(pkg) {
+				add(guessModuleRoot(pkg), "", ConfidenceMedium)
+			}
+		}
+	}
+
+	// 函数所属的包：覆盖没有版本信息的依赖
+	known := make([]string, 0, len(modules))
+	for modPath := range modules {
+		known = append(known, modPath)
+	}
+	for _, pkg := range pkgs {
+		confidence := ConfidenceLow
+		if j := strings.LastIndex(pkg, "/vendor/"); j >= 0 {
+			// GOPATH模式下vendor目录中的包，其导入路径包含vendor前缀
+			pkg = pkg[j+len("/vendor/"):]
+			confidence = ConfidenceMedium
+		}
+		if pkg == "main" || isStdPackage(pkg) ||
+			(result.mainModule != "" && hasPathPrefix(pkg, result.mainModule)) {
+			continue
+		}
+		covered := false
+		for _, modPath := range known {
+			if hasPathPrefix(pkg, modPath) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			add(guessModuleRoot(pkg), "", confidence)
+		}
+	}
+
+	result.deps = make([]DependencyInfo, 0, len(modules))
+	for _, dep := range modules {
+		result.deps = append(result.deps, *dep)
+	}
+	sort.Slice(result.deps, func(i, j int) bool {
+		return result.deps[i].Path < result.deps[j].Path
+	})
+	return result
+}
+
+// splitSourceRoot 比较包的源码目录和导入路径的公共后缀，返回剩余的目录前缀和导入路径前缀。
+// 例如 /src/app/pkg/util 和 github.com/x/app/pkg/util 返回 /src/app 和 github.com/x/app。
+// 导入路径前缀不会短于推测的模块根路径，因为检出目录通常与模块路径的最后一段同名。
+func splitSourceRoot(dir, pkg string) (root, importRoot string, ok bool) {
+	minRoot := guessModuleRoot(pkg)
+	matched := false
+	for pkg != minRoot && dir != "/" && dir != "." && path.Base(pkg) == path.Base(dir) {
+		pkg, dir = path.Dir(pkg), path.Dir(dir)
+		matched = true
+	}
+	if !matched {
+		return "", "", false
+	}
+	return dir, pkg, true
+}
+
+// parseModuleCachePath 从源文件路径中解析模块路径和版本，
+// 支持模块缓存中的绝对路径和使用 -trimpath 编译时的路径。
+// rest 是文件相对于模块根目录的路径。
+func parseModuleCachePath(file string) (modPath, version, rest string, ok bool) {
+	if i := strings.Index(file, "/pkg/mod/"); i >= 0 {
+		file = file[i+len("/pkg/mod/"):]
+	} else if isAbsPath(file) {
+		return "", "", "", false
+	}
+
+	at := strings.IndexByte(file, '@')
+	if at <= 0 {
+		return "", "", "", false
+	}
+	slash := strings.IndexByte(file[at:], '/')
+	if slash < 0 {
+		return "", "", "", false
+	}
+
+	modPath, err := unescapeModulePath(file[:at])
+	if err != nil || !strings.Contains(modPath, ".") {
+		return "", "", "", false
+	}
+	version = file[at+1 : at+slash]
+	if !strings.HasPrefix(version, "v") {
+		return "", "", "", false
+	}
+	// 通过GOTOOLCHAIN下载的工具链也位于模块缓存中，其源文件属于标准库
+	if modPath == "golang.org/toolchain" {
+		return "", "", "", false
+	}
+	return modPath, version, file[at+slash+1:], true
+}
+
+// unescapeModulePath 还原模块缓存中转义的路径，缓存中的大写字母X被存储为 !x
+func unescapeModulePath(escaped string) (string, error) {
+	if !strings.Contains(escaped, "!") {
+		return escaped, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		c := escaped[i]
+		if c != '!' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 >= len(escaped) || escaped[i+1] < 'a' || escaped[i+1] > 'z' {
+			return "", strconv.ErrSyntax
+		}
+		i++
+		b.WriteByte(escaped[i] - 'a' + 'A')
+	}
+	return b.String(), nil
+}
+
+// threeElementHosts 是模块路径通常由三段组成的代码托管域名
+var threeElementHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"gitee.com":     true,
+	"codeberg.org":  true,
+	"golang.org":    true,
+}
+
+// guessModuleRoot 根据导入路径推测其所属的模块根路径，例如
+// github.com/x/y/z -> github.com/x/y，gopkg.in/yaml.v2 -> gopkg.in/yaml.v2
+func guessModuleRoot(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	if !strings.Contains(elems[0], ".") {
+		return elems[0]
+	}
+	n := 2
+	if threeElementHosts[elems[0]] {
+		n = 3
+	}
+	if len(elems) < n {
+		n = len(elems)
+	}
+	return strings.Join(elems[:n], "/")
+}
+
+// isAbsPath 判断pclntab中记录的路径是否为绝对路径（包括Windows盘符路径）
+func isAbsPath(p string) bool {
+	return strings.HasPrefix(p, "/") || (len(p) >= 3 && p[1] == ':' && (p[2] == '/' || p[2] == '\\'))
+}
+
+// confidenceRank 返回可信度的排序值，便于比较
+func confidenceRank(c Confidence) int {
+	switch c {
+	case ConfidenceHigh:
+		return 3
+	case ConfidenceMedium:
+		return 2
+	case ConfidenceLow:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseBinaryFromBytes_Degraded(t *testing.T) {
+	data, err := os.ReadFile(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test binary: %v", err)
+	}
+
+	// 破坏构建信息的魔数，模拟没有构建信息的二进制文件
+	magic := []byte("\xff Go buildinf:")
+	if !bytes.Contains(data, magic) {
+		t.Skip("Test binary has no build info")
+	}
+	data = bytes.ReplaceAll(data, magic, []byte("\xff Go xxxxxxxx:"))
+
+	info, err := ParseBinaryFromBytes(data)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromBytes() error = %v, want degraded result", err)
+	}
+	if info.Degraded == nil {
+		t.Fatal("Expected Degraded to be set")
+	}
+	if info.Degraded.Reason == "" {
+		t.Error("Expected a degradation reason")
+	}
+	if strings.HasPrefix(runtime.Version(), "go1") && info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q (source %q)", info.GoVersion, runtime.Version(), info.Degraded.GoVersionSource)
+	}
+	for _, dep := range info.Dependencies {
+		if dep.Confidence == "" {
+			t.Errorf("Dependency %s has no confidence", dep.Path)
+		}
+		if isStdPackage(dep.Path) {
+			t.Errorf("Standard library package %s reported as dependency", dep.Path)
+		}
+	}
+}
+
+func TestParseBinaryFromBytes_NotGoBinary(t *testing.T) {
+	// 没有pclntab的数据无法降级解析，仍然返回错误
+	if _, err := ParseBinaryFromBytes([]byte("\x7fELF not really an executable")); err == nil {
+		t.Error("Expected error for non-Go data")
+	}
+}
+
+func TestParseModuleCachePath(t *testing.T) {
+	tests := []struct {
+		file    string
+		modPath string
+		version string
+		rest    string
+		ok      bool
+	}{
+		{"/home/u/go/pkg/mod/github.com/spf13/cobra@v1.9.1/command.go", "github.com/spf13/cobra", "v1.9.1", "command.go", true},
+		{"/home/u/go/pkg/mod/github.com/!burnt!sushi/toml@v1.3.2/decode.go", "github.com/BurntSushi/toml", "v1.3.2", "decode.go", true},
+		{"C:/Users/u/go/pkg/mod/golang.org/x/sys@v0.31.0/unix/syscall.go", "golang.org/x/sys", "v0.31.0", "unix/syscall.go", true},
+		{"gopkg.in/yaml.v3@v3.0.1/yaml.go", "gopkg.in/yaml.v3", "v3.0.1", "yaml.go", true},
+		{"/home/u/go/pkg/mod/golang.org/toolchain@v0.0.1-go1.22.0.linux-amd64/src/runtime/proc.go", "", "", "", false},
+		{"/usr/local/go/src/runtime/proc.go", "", "", "", false},
+		{"/home/u/src/github.com/x/y/main.go", "", "", "", false},
+		{"runtime/proc.go", "", "", "", false},
+	}
+	for _, tt := range tests {
+		modPath, version, rest, ok := parseModuleCachePath(tt.file)
+		if modPath != tt.modPath || version != tt.version || rest != tt.rest || ok != tt.ok {
+			t.Errorf("parseModuleCachePath(%q) = %q, %q, %q, %v, want %q, %q, %q, %v",
+				tt.file, modPath, version, rest, ok, tt.modPath, tt.version, tt.rest, tt.ok)
+		}
+	}
+}
+
+func TestGuessModuleRoot(t *testing.T) {
+	tests := map[string]string{
+		"github.com/spf13/cobra/doc":  "github.com/spf13/cobra",
+		"golang.org/x/sys/unix":       "golang.org/x/sys",
+		"gopkg.in/yaml.v2":            "gopkg.in/yaml.v2",
+		"go.uber.org/zap/zapcore":     "go.uber.org/zap",
+		"github.com/pkg":              "github.com/pkg",
+		"myapp/internal/config":       "myapp",
+		"k8s.io/client-go/kubernetes": "k8s.io/client-go",
+	}
+	for importPath, want := range tests {
+		if got := guessModuleRoot(importPath); got != want {
+			t.Errorf("guessModuleRoot(%q) = %q, want %q", importPath, got, want)
+		}
+	}
+}
+
+func TestSplitSourceRoot(t *testing.T) {
+	tests := []struct {
+		dir, pkg         string
+		root, importRoot string
+		ok               bool
+	}{
+		{"/src/app/pkg/util", "github.com/x/app/pkg/util", "/src/app", "github.com/x/app", true},
+		{"/home/u/app/cmd/app", "github.com/x/app/cmd/app", "/home/u/app", "github.com/x/app", true},
+		{"/src/app", "github.com/x/app", "", "", false},
+		{"/src/vendor/github.com/x/y", "github.com/x/y", "", "", false},
+		{"/src/other", "github.com/x/app", "", "", false},
+	}
+	for _, tt := range tests {
+		root, importRoot, ok := splitSourceRoot(tt.dir, tt.pkg)
+		if root != tt.root || importRoot != tt.importRoot || ok != tt.ok {
+			t.Errorf("splitSourceRoot(%q, %q) = %q, %q, %v, want %q, %q, %v",
+				tt.dir, tt.pkg, root, importRoot, ok, tt.root, tt.importRoot, tt.ok)
+		}
+	}
+}
@@ -2,7 +2,7 @@ package gobinaryparser
 
 import (
 	"context"
-	"debug/buildinfo"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,7 +10,7 @@ import (
 )
 
 // ParseBinaryFromURL 下载并解析给定URL的Go二进制文件。
-// 此函数有30秒的默认超时，可以通过提供自定义上下文来自定义超时时间。
+// 此函数有30秒的默认超时，可以通过提供自定义上下文或使用 NewParser 和 WithTimeout 来自定义超时时间。
 //
 // 参数:
 //   - url: Go二进制文件的URL
@@ -28,14 +28,20 @@ import (
 //	fmt.Printf("Go版本: %s\n", info.GoVersion)
 //	fmt.Printf("依赖数量: %d\n", len(info.Dependencies))
 func ParseBinaryFromURL(url string) (*BinaryInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	return defaultParser.ParseBinaryFromURL(url)
+}
+
+// ParseBinaryFromURL 使用解析器的超时时间下载并解析给定URL的Go二进制文件，参见 ParseBinaryFromURL
+func (p *Parser) ParseBinaryFromURL(url string) (*BinaryInfo, error) {
+	ctx, cancel := p.timeoutContext()
 	defer cancel()
 
-	return ParseBinaryFromURLWithContext(ctx, url)
+	return p.ParseBinaryFromURLWithContext(ctx, url)
 }
 
 // ParseBinaryFromURLWithContext 使用自定义上下文下载并解析给定URL的Go二进制文件。
 // 上下文可用于设置超时、取消或传递请求特定的值。
+// 下载的内容按 ParseBinaryFromStream 的方式缓存，超过 DefaultMaxStreamSize 时返回 ErrTooLarge。
 //
 // 参数:
 //   - ctx: 上下文，用于控制请求的生命周期
@@ -60,29 +66,46 @@ func ParseBinaryFromURL(url string) (*BinaryInfo, error) {
 //		}
 //	}
 func ParseBinaryFromURLWithContext(ctx context.Context, url string) (*BinaryInfo, error) {
+	return defaultParser.ParseBinaryFromURLWithContext(ctx, url)
+}
+
+// ParseBinaryFromURLWithContext 使用解析器的HTTP客户端、重试策略和下载大小限制下载并解析给定URL的Go二进制文件，
+// 参见 ParseBinaryFromURLWithContext
+func (p *Parser) ParseBinaryFromURLWithContext(ctx context.Context, url string) (*BinaryInfo, error) {
+	if info, ok := p.cached(url); ok {
+		return info, nil
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, newParseError(url, "url", fmt.Errorf("创建请求失败: %w", err))
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := p.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("从URL下载二进制文件失败: %w", err)
+		return nil, newParseError(url, "url", fmt.Errorf("从URL下载二进制文件失败: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP错误: %s", resp.Status)
+		return nil, newParseError(url, "url", httpStatusError(resp))
 	}
 
-	// 读取整个响应体
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应内容失败: %w", err)
+	var body io.Reader = resp.Body
+	if p.progress != nil {
+		body = newProgressReader(resp.Body, url, resp.ContentLength, p.progress)
 	}
 
-	// 解析下载的二进制字节
-	return ParseBinaryFromBytes(data)
+	// 响应体缓存到内存或临时文件中，Content-Length超过大小限制时不下载
+	result, err := p.parseStream(ctx, body, &StreamOptions{
+		MaxSize:  p.maxDownloadSize,
+		SizeHint: resp.ContentLength,
+	}, url, "url")
+	if err != nil {
+		return nil, err
+	}
+	p.store(url, result)
+	return result, nil
 }
 
 // ParseBinaryFromRemoteFile 从支持范围请求的远程位置解析Go二进制文件。
@@ -104,10 +127,15 @@ func ParseBinaryFromURLWithContext(ctx context.Context, url string) (*BinaryInfo
 //	}
 //	fmt.Printf("主模块: %s@%s\n", info.Path, info.Version)
 func ParseBinaryFromRemoteFile(url string) (*BinaryInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	return defaultParser.ParseBinaryFromRemoteFile(url)
+}
+
+// ParseBinaryFromRemoteFile 使用解析器的超时时间从远程位置解析Go二进制文件，参见 ParseBinaryFromRemoteFile
+func (p *Parser) ParseBinaryFromRemoteFile(url string) (*BinaryInfo, error) {
+	ctx, cancel := p.timeoutContext()
 	defer cancel()
 
-	return ParseBinaryFromRemoteFileWithContext(ctx, url)
+	return p.ParseBinaryFromRemoteFileWithContext(ctx, url)
 }
 
 // ParseBinaryFromRemoteFileWithContext 使用自定义上下文从远程位置解析Go二进制文件。
@@ -130,22 +158,51 @@ func ParseBinaryFromRemoteFile(url string) (*BinaryInfo, error) {
 //		log.Fatalf("解析失败: %v", err)
 //	}
 func ParseBinaryFromRemoteFileWithContext(ctx context.Context, url string) (*BinaryInfo, error) {
-	// 实现一个自定义的io.ReaderAt，用于进行范围请求
-	reader := NewHTTPReaderAt(url)
+	return defaultParser.ParseBinaryFromRemoteFileWithContext(ctx, url)
+}
 
-	// 使用reader解析二进制文件
-	info, err := buildinfo.Read(reader)
-	if err != nil {
-		return nil, fmt.Errorf("从远程文件读取构建信息失败: %w", err)
+// ParseBinaryFromRemoteFileWithContext 使用解析器的HTTP客户端和重试策略从远程位置解析Go二进制文件，
+// 参见 ParseBinaryFromRemoteFileWithContext
+func (p *Parser) ParseBinaryFromRemoteFileWithContext(ctx context.Context, url string) (*BinaryInfo, error) {
+	if info, ok := p.cached(url); ok {
+		return info, nil
 	}
 
-	return createBinaryInfo(info, url, "url")
+	// 实现一个自定义的io.ReaderAt，用于进行范围请求，所有请求都使用调用者的上下文
+	reader := p.NewHTTPReaderAt(url).WithContext(ctx)
+	// 格式识别在读取失败后还会尝试读取其他位置，遇到无法恢复的错误后不再请求
+	reader.failFast = true
+	defer reader.Close()
+
+	// 使用reader解析二进制文件，HTTPReaderAt无法确定大小，不进行深度扫描
+	result, err := readBinaryInfo(reader, url, "url")
+	stats := reader.Stats()
+	p.logger.Debug("远程文件读取完成", "url", url, "requests", stats.Requests, "bytes", stats.BytesFetched,
+		"cache_hits", stats.CacheHits, "cache_misses", stats.CacheMisses, "coalesced", stats.Coalesced)
+	if err != nil {
+		// 格式识别会吞掉读取错误，请求失败时返回请求的错误
+		if readErr := reader.cache.readErr(); readErr != nil {
+			err = readErr
+		} else if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, newParseError(url, "url", err)
+	}
+	result.RemoteStats = &stats
+	p.store(url, result)
+	return result, nil
 }
 
-// HTTPReaderAt 实现了用于HTTP范围请求的io.ReaderAt接口
+// HTTPReaderAt 实现了用于HTTP范围请求的io.ReaderAt接口。
+// 读取的数据按块缓存，缓存由 WithContext 返回的读取器共享，配置参见 HTTPCacheOptions 和 WithHTTPCache。
+// HTTPReaderAt可以被多个goroutine同时使用。
 type HTTPReaderAt struct {
-	url string
-	ctx context.Context
+	url    string
+	ctx    context.Context
+	parser *Parser
+	cache  *blockCache
+
+	failFast bool // 遇到无法恢复的错误后，之后的读取直接返回该错误
 }
 
 // NewHTTPReaderAt 为给定URL创建新的HTTPReaderAt
@@ -161,9 +218,16 @@ type HTTPReaderAt struct {
 //	reader := binaryparser.NewHTTPReaderAt("https://example.com/file.bin")
 //	// 现在可以对reader执行ReadAt操作
 func NewHTTPReaderAt(url string) *HTTPReaderAt {
+	return defaultParser.NewHTTPReaderAt(url)
+}
+
+// NewHTTPReaderAt 为给定URL创建使用解析器的HTTP客户端、User-Agent和重试策略的HTTPReaderAt
+func (p *Parser) NewHTTPReaderAt(url string) *HTTPReaderAt {
 	return &HTTPReaderAt{
-		url: url,
-		ctx: context.Background(),
+		url:    url,
+		ctx:    context.Background(),
+		parser: p,
+		cache:  newBlockCache(p.httpCache),
 	}
 }
 
@@ -184,11 +248,30 @@ func NewHTTPReaderAt(url string) *HTTPReaderAt {
 //	readerWithTimeout := reader.WithContext(ctx)
 func (h *HTTPReaderAt) WithContext(ctx context.Context) *HTTPReaderAt {
 	return &HTTPReaderAt{
-		url: h.url,
-		ctx: ctx,
+		url:    h.url,
+		ctx:    ctx,
+		parser: h.parser,
+		cache:  h.cache,
+
+		failFast: h.failFast,
 	}
 }
 
+// Stats 返回读取器（包括通过 WithContext 派生的读取器）发送的请求数、接收的字节数和缓存命中情况
+//
+// 返回:
+//   - HTTPReaderStats: 当前的读取统计
+//
+// 使用示例:
+//
+//	reader := binaryparser.NewHTTPReaderAt("https://example.com/file.bin")
+//	info, err := buildinfo.Read(reader)
+//	stats := reader.Stats()
+//	fmt.Printf("请求数: %d, 下载字节数: %d\n", stats.Requests, stats.BytesFetched)
+func (h *HTTPReaderAt) Stats() HTTPReaderStats {
+	return h.cache.stats()
+}
+
 // ReadAt 实现io.ReaderAt接口
 //
 // 参数:
@@ -197,31 +280,124 @@ func (h *HTTPReaderAt) WithContext(ctx context.Context) *HTTPReaderAt {
 //
 // 返回:
 //   - n: 读取的字节数
-//   - err: 如果读取过程中发生错误，则返回错误信息
+//   - err: 如果读取过程中发生错误，则返回错误信息；读取超出文件末尾时返回 io.EOF
 //
 // 该方法通过HTTP Range头从远程文件读取特定范围的内容，这对于大文件特别有用，
-// 因为它允许只下载需要的部分，而不是整个文件。
+// 因为它允许只下载需要的部分，而不是整个文件。已读取的块从缓存中返回，
+// 缺失的连续块合并为一个请求并预读其后的块，其他goroutine正在请求的块不会重复请求。
 func (h *HTTPReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("无效的偏移量: %d", off)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if h.failFast {
+		if err := h.cache.readErr(); isFatalReadError(err) {
+			return 0, err
+		}
+	}
+	n, err = h.cache.readAt(h, p, off)
+	if err != nil && err != io.EOF {
+		h.cache.setReadErr(err)
+	}
+	return n, err
+}
+
+// Close 清空缓存的块，并删除服务器不支持范围请求时下载的完整文件（可能位于临时文件中）。
+// Close之后仍然可以继续读取，数据会重新请求。
+func (h *HTTPReaderAt) Close() error {
+	return h.cache.close()
+}
+
+// isFatalReadError 判断读取错误是否无法通过再次请求恢复：重试已耗尽、文件已变化或超过下载大小限制
+func isFatalReadError(err error) bool {
+	var rerr *RetryError
+	return errors.As(err, &rerr) || errors.Is(err, ErrRemoteChanged) || errors.Is(err, ErrTooLarge)
+}
+
+// get 发送请求文件[start, end]范围的GET请求，ifRange不为空时设置If-Range头
+func (h *HTTPReaderAt) get(start, end int64, ifRange string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(h.ctx, http.MethodGet, h.url, nil)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// 设置Range头
-	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if ifRange != "" {
+		req.Header.Set("If-Range", ifRange)
+	}
 
-	// 发送请求
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return 0, err
+	return h.parser.do(req)
+}
+
+// httpStatusError 返回表示HTTP错误状态的错误，404和410包装 ErrNotFound
+func httpStatusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return fmt.Errorf("%w: HTTP错误: %s", ErrNotFound, resp.Status)
+	}
+	return fmt.Errorf("HTTP错误: %s", resp.Status)
+}
+
+// do 发送HTTP请求，设置User-Agent、自定义请求头、基本认证和Cookie，网络错误以及429、500、502、503和504状态码按重试策略重试。
+// 重试后仍然失败时返回 *RetryError，不返回这些状态码的响应；其他状态码的响应直接返回。
+func (p *Parser) do(req *http.Request) (*http.Response, error) {
+	if p.userAgent != "" {
+		req.Header.Set("User-Agent", p.userAgent)
+	}
+	for key, values := range p.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if p.basicAuth != nil {
+		req.SetBasicAuth(p.basicAuth.username, p.basicAuth.password)
+	}
+	for _, cookie := range p.cookies {
+		req.AddCookie(cookie)
 	}
-	defer resp.Body.Close()
 
-	// 检查正确的状态码（206 Partial Content）
-	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("HTTP错误: %s", resp.Status)
+	ctx := req.Context()
+	attempts := p.retry.attempts()
+	for attempt := 1; ; attempt++ {
+		p.logger.Debug("发送HTTP请求", "method", req.Method, "url", req.URL.String(), "range", req.Header.Get("Range"), "attempt", attempt)
+		resp, err := p.client.Do(req)
+		if !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		status := ""
+		if resp != nil {
+			status = resp.Status
+			err = httpStatusError(resp)
+			resp.Body.Close()
+		}
+		if attempt >= attempts {
+			return nil, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		wait := p.retry.delay(attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// 等待会超过上下文的截止时间，继续重试没有意义
+			return nil, &RetryError{Attempts: attempt, Err: fmt.Errorf("%w，下次重试需要等待%v，超过了上下文的截止时间", err, wait)}
+		}
+		p.logger.Warn("HTTP请求失败，稍后重试", "url", req.URL.String(), "attempt", attempt, "status", status, "error", err, "wait", wait)
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, &RetryError{Attempts: attempt, Err: err}
+		}
 	}
+}
 
-	// 读取响应体
-	return io.ReadFull(resp.Body, p)
+// shouldRetry 判断HTTP请求的结果是否应该重试
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	// 501等其他状态码表示服务器无法处理该请求，重试也不会成功
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
 }
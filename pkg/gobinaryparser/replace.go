@@ -0,0 +1,127 @@
+package gobinaryparser
+
+import "strings"
+
+// ReplaceKind 表示replace指令的类型
+type ReplaceKind string
+
+const (
+	ReplaceNone     ReplaceKind = ""          // 没有被替换
+	ReplaceFork     ReplaceKind = "fork"      // 替换为另一个模块路径，例如 fork 出的仓库
+	ReplacePin      ReplaceKind = "pin"       // 替换为同一模块的另一个版本
+	ReplaceLocal    ReplaceKind = "local"     // 替换为本地目录，版本为 (devel)，没有校验和
+	ReplaceSamePath ReplaceKind = "same-path" // 替换为同一模块的同一版本，实际编译的代码不变
+)
+
+// EffectiveModule 表示实际编译进二进制文件的模块，即应用replace指令后的结果
+// 示例：
+//
+//	{
+//	  "path": "github.com/myfork/cobra",
+//	  "version": "v1.6.2",
+//	  "sum": "h1:7ehsRX9Usi7fGZZ71xJ9m3mqKmWnuE/UvNoza7PkHF4=",
+//	  "replace_kind": "fork",
+//	  "requested": {"path": "github.com/spf13/cobra", "version": "v1.6.1"}
+//	}
+type EffectiveModule struct {
+	Path        string          `json:"path"`                   // 实际使用的模块路径，替换为本地目录时为目录路径
+	Version     string          `json:"version"`                // 实际使用的版本，替换为本地目录时为 (devel)
+	Sum         string          `json:"sum,omitempty"`          // 实际使用的模块的校验和
+	ReplaceKind ReplaceKind     `json:"replace_kind,omitempty"` // replace指令的类型，没有被替换时为空
+	Requested   *DependencyInfo `json:"requested,omitempty"`    // 被替换前go.mod中要求的模块，没有被替换时为nil
+}
+
+// Replaced 判断模块是否经过了replace指令替换
+func (m EffectiveModule) Replaced() bool {
+	return m.ReplaceKind != ReplaceNone
+}
+
+// IsLocal 判断模块是否被替换为本地目录
+func (m EffectiveModule) IsLocal() bool {
+	return m.ReplaceKind == ReplaceLocal
+}
+
+// ReplaceKind 返回依赖的replace指令的类型
+//
+// 返回:
+//   - ReplaceKind: replace指令的类型，没有被替换时返回 ReplaceNone
+func (dep DependencyInfo) ReplaceKind() ReplaceKind {
+	switch {
+	case dep.Replace == nil:
+		return ReplaceNone
+	case isLocalReplace(dep.Replace):
+		return ReplaceLocal
+	case dep.Replace.Path != dep.Path:
+		return ReplaceFork
+	case dep.Replace.Version != dep.Version:
+		return ReplacePin
+	}
+	return ReplaceSamePath
+}
+
+// isLocalReplace 判断替换模块是否为本地目录。
+// 构建信息中本地目录替换记录为 "=> ../lib (devel)"，没有版本时再根据路径是否为文件系统路径判断。
+func isLocalReplace(replace *DependencyInfo) bool {
+	return replace.Version == develVersion || replace.Version == "" || isFilesystemPath(replace.Path)
+}
+
+// isFilesystemPath 判断replace指令的目标是否为文件系统路径，即以 ./、../、/ 或Windows盘符开头，
+// 与go命令区分模块路径和目录的规则一致
+func isFilesystemPath(path string) bool {
+	for _, prefix := range []string{"./", "../", "/", `.\`, `..\`, `\`} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	if path == "." || path == ".." {
+		return true
+	}
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '/' || path[2] == '\\') &&
+		('a' <= path[0] && path[0] <= 'z' || 'A' <= path[0] && path[0] <= 'Z')
+}
+
+// Effective 返回实际编译进二进制文件的模块。
+// 依赖被替换时返回替换模块的路径、版本和校验和，并在Requested中保留原始要求；
+// 没有被替换时返回依赖本身。
+//
+// 返回:
+//   - EffectiveModule: 实际使用的模块
+//
+// 使用示例:
+//
+//	for _, dep := range info.Dependencies {
+//		m := dep.Effective()
+//		if m.IsLocal() {
+//			fmt.Printf("%s 使用了本地目录 %s\n", dep.Path, m.Path)
+//		}
+//	}
+func (dep DependencyInfo) Effective() EffectiveModule {
+	if dep.Replace == nil {
+		return EffectiveModule{Path: dep.Path, Version: dep.Version, Sum: dep.Sum}
+	}
+	return EffectiveModule{
+		Path:        dep.Replace.Path,
+		Version:     dep.Replace.Version,
+		Sum:         dep.Replace.Sum,
+		ReplaceKind: dep.ReplaceKind(),
+		Requested:   &DependencyInfo{Path: dep.Path, Version: dep.Version, Sum: dep.Sum},
+	}
+}
+
+// EffectiveDependencies 返回所有依赖实际编译进二进制文件的模块，顺序与 Dependencies 相同
+//
+// 返回:
+//   - []EffectiveModule: 应用replace指令后的模块列表
+//
+// 使用示例:
+//
+//	for _, m := range info.EffectiveDependencies() {
+//		fmt.Printf("%s@%s\n", m.Path, m.Version)
+//	}
+func (info *BinaryInfo) EffectiveDependencies() []EffectiveModule {
+	modules := make([]EffectiveModule, 0, len(info.Dependencies))
+	for _, dep := range info.Dependencies {
+		modules = append(modules, dep.Effective())
+	}
+	return modules
+}
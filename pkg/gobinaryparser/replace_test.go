@@ -0,0 +1,102 @@
+package gobinaryparser
+
+import "testing"
+
+func TestDependencyInfo_Effective(t *testing.T) {
+	tests := []struct {
+		name string
+		dep  DependencyInfo
+		want EffectiveModule
+	}{
+		{
+			name: "not replaced",
+			dep:  DependencyInfo{Path: "github.com/a/b", Version: "v1.0.0", Sum: "h1:a"},
+			want: EffectiveModule{Path: "github.com/a/b", Version: "v1.0.0", Sum: "h1:a"},
+		},
+		{
+			name: "fork",
+			dep:  DependencyInfo{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "github.com/fork/b", Version: "v1.0.1", Sum: "h1:f"}},
+			want: EffectiveModule{Path: "github.com/fork/b", Version: "v1.0.1", Sum: "h1:f", ReplaceKind: ReplaceFork},
+		},
+		{
+			name: "pin",
+			dep:  DependencyInfo{Path: "github.com/a/b", Version: "v1.2.0", Replace: &DependencyInfo{Path: "github.com/a/b", Version: "v1.1.0"}},
+			want: EffectiveModule{Path: "github.com/a/b", Version: "v1.1.0", ReplaceKind: ReplacePin},
+		},
+		{
+			name: "local",
+			dep:  DependencyInfo{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../b", Version: "(devel)"}},
+			want: EffectiveModule{Path: "../b", Version: "(devel)", ReplaceKind: ReplaceLocal},
+		},
+		{
+			name: "local windows path",
+			dep:  DependencyInfo{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: `C:\src\b`}},
+			want: EffectiveModule{Path: `C:\src\b`, ReplaceKind: ReplaceLocal},
+		},
+		{
+			name: "same path",
+			dep:  DependencyInfo{Path: "github.com/a/b", Version: "v1.0.0", Replace: &DependencyInfo{Path: "github.com/a/b", Version: "v1.0.0"}},
+			want: EffectiveModule{Path: "github.com/a/b", Version: "v1.0.0", ReplaceKind: ReplaceSamePath},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.dep.Effective()
+			if got.Path != tt.want.Path || got.Version != tt.want.Version || got.Sum != tt.want.Sum || got.ReplaceKind != tt.want.ReplaceKind {
+				t.Errorf("Effective() = %+v, want %+v", got, tt.want)
+			}
+			if got.Replaced() != (tt.dep.Replace != nil) {
+				t.Errorf("Replaced() = %v", got.Replaced())
+			}
+			if got.IsLocal() != (tt.want.ReplaceKind == ReplaceLocal) {
+				t.Errorf("IsLocal() = %v", got.IsLocal())
+			}
+			if tt.dep.Replace == nil {
+				if got.Requested != nil {
+					t.Errorf("Expected no Requested module, got %+v", got.Requested)
+				}
+				return
+			}
+			if got.Requested == nil || got.Requested.Path != tt.dep.Path || got.Requested.Version != tt.dep.Version {
+				t.Errorf("Requested = %+v, want %s@%s", got.Requested, tt.dep.Path, tt.dep.Version)
+			}
+		})
+	}
+}
+
+func TestBinaryInfo_EffectiveDependencies(t *testing.T) {
+	info := &BinaryInfo{
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/a/b", Version: "v1.0.0"},
+			{Path: "github.com/c/d", Version: "v1.0.0", Replace: &DependencyInfo{Path: "../d", Version: "(devel)"}},
+		},
+	}
+
+	modules := info.EffectiveDependencies()
+	if len(modules) != 2 {
+		t.Fatalf("EffectiveDependencies() returned %d modules, want 2", len(modules))
+	}
+	if modules[0].Path != "github.com/a/b" || modules[1].Path != "../d" || !modules[1].IsLocal() {
+		t.Errorf("EffectiveDependencies() = %+v", modules)
+	}
+}
+
+func TestIsFilesystemPath(t *testing.T) {
+	tests := map[string]bool{
+		"./lib":              true,
+		"../lib":             true,
+		"/src/lib":           true,
+		`..\lib`:             true,
+		`C:\src\lib`:         true,
+		"c:/src/lib":         true,
+		"example.com/lib":    false,
+		"github.com/a/b/../": false,
+		"C:lib":              false,
+	}
+	for path, want := range tests {
+		if got := isFilesystemPath(path); got != want {
+			t.Errorf("isFilesystemPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
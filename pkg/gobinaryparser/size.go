@@ -0,0 +1,272 @@
+package gobinaryparser
+
+import (
+	"debug/gosym"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// AnalyzeSize 分析Go二进制文件的体积构成，将代码和数据字节归属到各个模块。
+// 代码字节按pclntab中的函数地址范围归属，数据字节按符号表中的符号大小归属；
+// 标准库、Go运行时、cgo链接的C代码以及编译器生成的元数据分别归入单独的分组。
+//
+// 参数:
+//   - filePath: Go二进制文件的路径
+//
+// 返回:
+//   - *SizeReport: 体积归属分析结果，模块按总字节数降序排列
+//   - error: 如果文件无法解析或不包含pclntab，则返回错误信息
+//
+// 使用示例:
+//
+//	report, err := gobinaryparser.AnalyzeSize("/usr/local/bin/kubectl")
+//	if err != nil {
+//		log.Fatalf("分析体积失败: %v", err)
+//	}
+//	for _, m := range report.Modules {
+//		fmt.Printf("%-50s %10d %6.2f%%\n", m.Path, m.Total(), m.Percent)
+//	}
+func AnalyzeSize(filePath string) (*SizeReport, error) {
+	info, err := ParseBinary(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开二进制文件失败: %w", err)
+	}
+	defer f.Close()
+
+	report, err := analyzeSize(info, f)
+	if err != nil {
+		return nil, err
+	}
+	if stat, err := f.Stat(); err == nil {
+		report.FileSize = stat.Size()
+	}
+	return report, nil
+}
+
+// AnalyzeSizeFromReader 从io.ReaderAt接口读取Go二进制文件并分析其体积构成。
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - *SizeReport: 体积归属分析结果，FileSize字段为0
+//   - error: 如果数据无法解析或不包含pclntab，则返回错误信息
+//
+// 使用示例:
+//
+//	data, _ := os.ReadFile("/usr/local/bin/go")
+//	report, err := gobinaryparser.AnalyzeSizeFromReader(bytes.NewReader(data))
+//	if err != nil {
+//		log.Fatalf("分析体积失败: %v", err)
+//	}
+//	fmt.Printf("已归属字节数: %d\n", report.AttributedSize)
+func AnalyzeSizeFromReader(r io.ReaderAt) (*SizeReport, error) {
+	info, err := ParseBinaryFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return analyzeSize(info, r)
+}
+
+// analyzeSize 根据已解析的构建信息对可执行文件进行体积归属
+func analyzeSize(info *BinaryInfo, r io.ReaderAt) (*SizeReport, error) {
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := exe.symbolTable()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SizeReport{}
+	for _, sect := range exe.sections() {
+		switch sect.Kind {
+		case symText:
+			report.TextSize += sect.End - sect.Start
+		case symRodata:
+			report.RodataSize += sect.End - sect.Start
+		case symData:
+			report.DataSize += sect.End - sect.Start
+		}
+	}
+
+	attr := newSizeAttributor(info)
+
+	// 代码字节：Go函数按pclntab中的地址范围归属
+	funcEntries := make(map[uint64]bool, len(table.Funcs))
+	for i := range table.Funcs {
+		fn := &table.Funcs[i]
+		funcEntries[fn.Entry] = true
+		if fn.End > fn.Entry {
+			attr.add(fn.Sym, symText, fn.End-fn.Entry)
+		}
+	}
+
+	// 符号表中的数据符号以及不在pclntab中的代码（例如cgo链接的C函数）
+	syms, err := exe.symbols()
+	if err == nil {
+		report.HasSymbols = true
+		for _, s := range syms {
+			if s.Size == 0 || s.Kind == symBSS {
+				continue
+			}
+			if s.Kind == symText && funcEntries[s.Addr] {
+				continue
+			}
+			attr.add(&gosym.Sym{Name: s.Name}, s.Kind, s.Size)
+		}
+	}
+
+	report.Modules = attr.results()
+	for _, m := range report.Modules {
+		report.AttributedSize += m.Total()
+	}
+	for i := range report.Modules {
+		if report.AttributedSize > 0 {
+			report.Modules[i].Percent = float64(report.Modules[i].Total()) * 100 / float64(report.AttributedSize)
+		}
+	}
+
+	return report, nil
+}
+
+// sizeAttributor 将符号的字节数累加到对应的模块或分组
+type sizeAttributor struct {
+	mainModule string
+	modules    []DependencyInfo // 按路径长度降序排列，以便最长前缀匹配
+	buckets    map[string]*ModuleSize
+}
+
+// newSizeAttributor 根据二进制文件的模块信息创建归属器
+func newSizeAttributor(info *BinaryInfo) *sizeAttributor {
+	attr := &sizeAttributor{
+		mainModule: info.modulePath,
+		modules:    append([]DependencyInfo(nil), info.Dependencies...),
+		buckets:    make(map[string]*ModuleSize),
+	}
+	sort.SliceStable(attr.modules, func(i, j int) bool {
+		return len(attr.modules[i].Path) > len(attr.modules[j].Path)
+	})
+	return attr
+}
+
+// add 将一个符号的字节数累加到其所属的分组
+func (a *sizeAttributor) add(sym *gosym.Sym, kind byte, size uint64) {
+	bucket := a.bucketFor(sym)
+	switch kind {
+	case symText:
+		bucket.TextBytes += size
+	case symRodata:
+		bucket.RodataBytes += size
+	case symData:
+		bucket.DataBytes += size
+	}
+}
+
+// bucketFor 返回符号所属的分组，不存在时创建
+func (a *sizeAttributor) bucketFor(sym *gosym.Sym) *ModuleSize {
+	path, version, kind := a.classify(sym)
+	bucket, ok := a.buckets[path]
+	if !ok {
+		bucket = &ModuleSize{Path: path, Version: version, Kind: kind}
+		a.buckets[path] = bucket
+	}
+	return bucket
+}
+
+// linkerBoundarySymbols 是链接器生成的段边界符号。
+// 在不记录符号大小的格式中，它们的推算大小覆盖了pclntab、类型信息等链接器元数据。
+var linkerBoundarySymbols = map[string]bool{
+	"runtime.text": true, "runtime.etext": true,
+	"runtime.rodata": true, "runtime.erodata": true,
+	"runtime.types": true, "runtime.etypes": true,
+	"runtime.typelink": true, "runtime.etypelink": true,
+	"runtime.itablink": true, "runtime.eitablink": true,
+	"runtime.pclntab": true, "runtime.epclntab": true,
+	"runtime.symtab": true, "runtime.esymtab": true,
+	"runtime.noptrdata": true, "runtime.enoptrdata": true,
+	"runtime.data": true, "runtime.edata": true,
+	"runtime.bss": true, "runtime.ebss": true,
+	"runtime.noptrbss": true, "runtime.enoptrbss": true,
+	"runtime.covctrs": true, "runtime.ecovctrs": true,
+	"runtime.gcdata": true, "runtime.gcbss": true,
+	"runtime.end": true,
+}
+
+// classify 判断符号归属的模块路径、版本和分组类型
+func (a *sizeAttributor) classify(sym *gosym.Sym) (path, version string, kind SizeBucketKind) {
+	if linkerBoundarySymbols[sym.Name] {
+		return string(SizeBucketOther), "", SizeBucketOther
+	}
+
+	pkg := symbolPackage(sym)
+	if pkg == "" {
+		name := sym.Name
+		switch {
+		case strings.HasPrefix(name, "go:") || strings.HasPrefix(name, "type:") ||
+			strings.HasPrefix(name, "go.") || strings.HasPrefix(name, "type."):
+			return string(SizeBucketOther), "", SizeBucketOther
+		case strings.HasPrefix(name, "_rt0_"):
+			// 运行时的汇编入口函数没有包名，例如 _rt0_amd64_linux
+			return string(SizeBucketRuntime), "", SizeBucketRuntime
+		}
+		// 其余没有包名的符号来自cgo链接的C代码（内部链接时它们也会出现在pclntab中）
+		return string(SizeBucketCgo), "", SizeBucketCgo
+	}
+
+	switch {
+	case pkg == "runtime" || strings.HasPrefix(pkg, "runtime/") || strings.HasPrefix(pkg, "internal/runtime/"):
+		return string(SizeBucketRuntime), "", SizeBucketRuntime
+	case pkg == "main":
+		return a.mainBucketPath(), "", SizeBucketMain
+	case isStdPackage(pkg):
+		return string(SizeBucketStdLib), "", SizeBucketStdLib
+	case a.mainModule != "" && hasPathPrefix(pkg, a.mainModule):
+		return a.mainModule, "", SizeBucketMain
+	}
+
+	for _, dep := range a.modules {
+		if hasPathPrefix(pkg, dep.Path) {
+			return dep.Path, dep.Version, SizeBucketModule
+		}
+	}
+	return string(SizeBucketOther), "", SizeBucketOther
+}
+
+// mainBucketPath 返回主模块分组的名称
+func (a *sizeAttributor) mainBucketPath() string {
+	if a.mainModule != "" {
+		return a.mainModule
+	}
+	return "main"
+}
+
+// results 返回按总字节数降序排列的归属结果
+func (a *sizeAttributor) results() []ModuleSize {
+	results := make([]ModuleSize, 0, len(a.buckets))
+	for _, bucket := range a.buckets {
+		results = append(results, *bucket)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Total() != results[j].Total() {
+			return results[i].Total() > results[j].Total()
+		}
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+// hasPathPrefix 判断导入路径是否属于给定的模块路径
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,86 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"debug/gosym"
+	"math"
+	"testing"
+)
+
+func TestAnalyzeSize(t *testing.T) {
+	report, err := AnalyzeSize(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("AnalyzeSize failed: %v", err)
+	}
+
+	if report.FileSize == 0 || report.TextSize == 0 {
+		t.Errorf("Expected non-zero file and text size, got %+v", report)
+	}
+	if report.AttributedSize == 0 || len(report.Modules) == 0 {
+		t.Fatalf("Expected attributed modules, got %+v", report)
+	}
+
+	var percent float64
+	kinds := make(map[SizeBucketKind]bool)
+	for i, m := range report.Modules {
+		percent += m.Percent
+		kinds[m.Kind] = true
+		if i > 0 && report.Modules[i-1].Total() < m.Total() {
+			t.Errorf("Expected modules sorted by total size, %s (%d) before %s (%d)",
+				report.Modules[i-1].Path, report.Modules[i-1].Total(), m.Path, m.Total())
+		}
+	}
+	if math.Abs(percent-100) > 0.01 {
+		t.Errorf("Expected percentages to sum to 100, got %f", percent)
+	}
+
+	for _, kind := range []SizeBucketKind{SizeBucketRuntime, SizeBucketStdLib, SizeBucketMain} {
+		if !kinds[kind] {
+			t.Errorf("Expected a %s bucket in the report", kind)
+		}
+	}
+}
+
+func TestAnalyzeSizeFromReader_Invalid(t *testing.T) {
+	_, err := AnalyzeSizeFromReader(bytes.NewReader([]byte("This is not a Go binary")))
+	if err == nil {
+		t.Error("Expected error when analyzing invalid data, got nil")
+	}
+}
+
+func TestSizeAttributorClassify(t *testing.T) {
+	attr := newSizeAttributor(&BinaryInfo{
+		modulePath: "github.com/example/app",
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/example/lib", Version: "v1.0.0"},
+			{Path: "github.com/example/lib/v2", Version: "v2.1.0"},
+		},
+	})
+
+	tests := []struct {
+		name string
+		path string
+		kind SizeBucketKind
+	}{
+		{"main.main", "github.com/example/app", SizeBucketMain},
+		{"github.com/example/app/internal/x.Run", "github.com/example/app", SizeBucketMain},
+		{"github.com/example/lib/v2/sub.(*T).M", "github.com/example/lib/v2", SizeBucketModule},
+		{"github.com/example/lib.F", "github.com/example/lib", SizeBucketModule},
+		{"runtime.mallocgc", "runtime", SizeBucketRuntime},
+		{"internal/runtime/atomic.Load", "runtime", SizeBucketRuntime},
+		{"_rt0_amd64_linux", "runtime", SizeBucketRuntime},
+		{"net/http.(*Client).Do", "stdlib", SizeBucketStdLib},
+		{"x_cgo_init", "cgo", SizeBucketCgo},
+		{"_cgo_77133bf98b3a_Cfunc_free", "cgo", SizeBucketCgo},
+		{"go:string.*", "other", SizeBucketOther},
+		{"runtime.pclntab", "other", SizeBucketOther},
+		{"example.org/unknown.F", "other", SizeBucketOther},
+	}
+
+	for _, tt := range tests {
+		path, _, kind := attr.classify(&gosym.Sym{Name: tt.name})
+		if path != tt.path || kind != tt.kind {
+			t.Errorf("classify(%q) = (%q, %q), want (%q, %q)", tt.name, path, kind, tt.path, tt.kind)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package gobinaryparser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SourcePathKind 表示pclntab中源文件路径的类型
+type SourcePathKind string
+
+const (
+	SourcePathGoroot    SourcePathKind = "goroot"       // Go标准库和运行时的源文件（GOROOT/src）
+	SourcePathModCache  SourcePathKind = "module-cache" // 模块缓存中的依赖源文件（GOPATH/pkg/mod）
+	SourcePathVendor    SourcePathKind = "vendor"       // vendor目录中的依赖源文件
+	SourcePathWorkspace SourcePathKind = "workspace"    // 主模块或本地替换的依赖所在的工作目录中的源文件
+	SourcePathGenerated SourcePathKind = "generated"    // 编译器生成的代码，例如 "<autogenerated>"
+)
+
+// PathLeakKind 表示源文件路径泄露的信息类型
+type PathLeakKind string
+
+const (
+	PathLeakUsername    PathLeakKind = "username"     // 主目录中的用户名，例如 /home/alice
+	PathLeakHostname    PathLeakKind = "hostname"     // 网络路径中的主机名，例如 //fileserver/share 或 /net/buildhost
+	PathLeakCIWorkspace PathLeakKind = "ci-workspace" // CI系统的工作目录，泄露了使用的CI平台和仓库名
+)
+
+// SourcePath 表示pclntab文件表中的一个源文件路径
+type SourcePath struct {
+	Path     string         `json:"path"`     // 链接器记录的源文件路径
+	Kind     SourcePathKind `json:"kind"`     // 路径类型
+	Absolute bool           `json:"absolute"` // 是否为绝对路径，使用 -trimpath 构建时所有路径都是相对路径
+}
+
+// SourceRoot 表示一类源文件所在的根目录，例如GOROOT或模块缓存目录
+type SourceRoot struct {
+	Kind  SourcePathKind `json:"kind"`  // 根目录下源文件的类型
+	Path  string         `json:"path"`  // 根目录的绝对路径
+	Files int            `json:"files"` // 根目录下的源文件数量
+}
+
+// PathLeak 表示源文件路径中泄露的一项构建环境信息
+// 示例：
+//
+//	{
+//	  "kind": "username",
+//	  "value": "alice",
+//	  "example": "/home/alice/src/myapp/main.go",
+//	  "files": 42
+//	}
+type PathLeak struct {
+	Kind    PathLeakKind `json:"kind"`    // 泄露的信息类型
+	Value   string       `json:"value"`   // 泄露的用户名、主机名或CI平台名称
+	Example string       `json:"example"` // 包含该信息的一个源文件路径
+	Files   int          `json:"files"`   // 包含该信息的源文件数量
+}
+
+// SourcePathReport 表示对二进制文件中源文件路径的审计结果
+type SourcePathReport struct {
+	Files   []SourcePath           `json:"files"`           // 按路径排序的所有源文件
+	Counts  map[SourcePathKind]int `json:"counts"`          // 每种类型的源文件数量
+	Trimmed bool                   `json:"trimmed"`         // 是否没有任何绝对路径，即使用了 -trimpath 构建
+	Roots   []SourceRoot           `json:"roots,omitempty"` // 源文件所在的绝对根目录，按文件数量降序排列
+	Leaks   []PathLeak             `json:"leaks,omitempty"` // 路径中泄露的构建环境信息
+}
+
+// AnalyzeSourcePaths 从pclntab的文件表中提取Go二进制文件的所有源文件路径，按GOROOT、模块缓存、
+// vendor和工作目录分类，并检查没有使用 -trimpath 构建时路径中泄露的用户名、主机名和CI工作目录。
+// pclntab在剥离了符号表的二进制文件中仍然存在，因此该函数也适用于使用 -ldflags=-s 构建的二进制文件。
+//
+// 参数:
+//   - filePath: Go二进制文件的路径
+//
+// 返回:
+//   - *SourcePathReport: 源文件路径的审计结果
+//   - error: 如果文件无法解析或不包含pclntab，则返回错误信息
+//
+// 使用示例:
+//
+//	report, err := gobinaryparser.AnalyzeSourcePaths("/usr/local/bin/myapp")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, leak := range report.Leaks {
+//		fmt.Printf("泄露的%s: %s (例如 %s)\n", leak.Kind, leak.Value, leak.Example)
+//	}
+func AnalyzeSourcePaths(filePath string) (*SourcePathReport, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开二进制文件失败: %w", err)
+	}
+	defer f.Close()
+
+	return AnalyzeSourcePathsFromReader(f)
+}
+
+// AnalyzeSourcePathsFromReader 从io.ReaderAt接口读取Go二进制文件并审计其中的源文件路径，
+// 参见 AnalyzeSourcePaths
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - *SourcePathReport: 源文件路径的审计结果
+//   - error: 如果数据不是受支持的可执行文件或不包含pclntab，则返回错误信息
+//
+// 使用示例:
+//
+//	data, _ := os.ReadFile("/usr/local/bin/myapp")
+//	report, err := gobinaryparser.AnalyzeSourcePathsFromReader(bytes.NewReader(data))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("使用了 -trimpath: %t\n", report.Trimmed)
+func AnalyzeSourcePathsFromReader(r io.ReaderAt) (*SourcePathReport, error) {
+	exe, err := openExecutable(r)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := exe.symbolTable()
+	if err != nil {
+		return nil, err
+	}
+
+	// 主模块路径用于识别 -trimpath 构建中主模块的相对路径
+	var mainModule string
+	if info, err := ParseBinaryFromReader(r); err == nil {
+		mainModule = info.modulePath
+	}
+
+	files := make([]string, 0, len(table.Files))
+	for file := range table.Files {
+		files = append(files, file)
+	}
+	return analyzeSourcePaths(files, mainModule), nil
+}
+
+// analyzeSourcePaths 对源文件路径进行分类并检查泄露的信息
+func analyzeSourcePaths(files []string, mainModule string) *SourcePathReport {
+	sort.Strings(files)
+	report := &SourcePathReport{
+		Files:   make([]SourcePath, 0, len(files)),
+		Counts:  make(map[SourcePathKind]int),
+		Trimmed: true,
+	}
+
+	goroot := detectGoroot(files)
+	roots := make(map[SourceRoot]int)
+	var workspace []string
+	leaks := make(map[PathLeak]*PathLeak)
+	var leakOrder []PathLeak
+
+	for _, file := range files {
+		sp := classifySourcePath(file, goroot, mainModule)
+		report.Files = append(report.Files, sp)
+		report.Counts[sp.Kind]++
+		if !sp.Absolute {
+			continue
+		}
+		report.Trimmed = false
+
+		normalized := normalizeSourcePath(file)
+		switch sp.Kind {
+		case SourcePathGoroot:
+			roots[SourceRoot{Kind: sp.Kind, Path: goroot}]++
+		case SourcePathModCache:
+			roots[SourceRoot{Kind: sp.Kind, Path: normalized[:strings.Index(normalized, "/pkg/mod/")+len("/pkg/mod")]}]++
+		case SourcePathVendor:
+			roots[SourceRoot{Kind: sp.Kind, Path: normalized[:strings.Index(normalized, "/vendor/")+len("/vendor")]}]++
+		case SourcePathWorkspace:
+			workspace = append(workspace, normalized)
+		}
+
+		for _, leak := range findPathLeaks(normalized) {
+			key := PathLeak{Kind: leak.Kind, Value: leak.Value}
+			if existing, ok := leaks[key]; ok {
+				existing.Files++
+				continue
+			}
+			leak.Example = file
+			leak.Files = 1
+			leaks[key] = &leak
+			leakOrder = append(leakOrder, key)
+		}
+	}
+
+	for dir, n := range workspaceRoots(workspace) {
+		roots[SourceRoot{Kind: SourcePathWorkspace, Path: dir}] += n
+	}
+	for root, n := range roots {
+		root.Files = n
+		report.Roots = append(report.Roots, root)
+	}
+	sort.Slice(report.Roots, func(i, j int) bool {
+		if report.Roots[i].Files != report.Roots[j].Files {
+			return report.Roots[i].Files > report.Roots[j].Files
+		}
+		return report.Roots[i].Path < report.Roots[j].Path
+	})
+
+	for _, key := range leakOrder {
+		report.Leaks = append(report.Leaks, *leaks[key])
+	}
+	return report
+}
+
+// classifySourcePath 判断源文件路径的类型，goroot为检测到的GOROOT目录，mainModule为主模块路径
+func classifySourcePath(file, goroot, mainModule string) SourcePath {
+	sp := SourcePath{Path: file}
+	if strings.HasPrefix(file, "<") || strings.HasPrefix(file, "_cgo_") {
+		sp.Kind = SourcePathGenerated
+		return sp
+	}
+
+	normalized := normalizeSourcePath(file)
+	sp.Absolute = isAbsSourcePath(normalized)
+	if !sp.Absolute {
+		// -trimpath 将GOROOT中的文件记录为包路径，将模块缓存中的文件记录为 module@version/路径
+		first, _, _ := strings.Cut(normalized, "/")
+		switch {
+		case moduleVersionPrefix(normalized) != "":
+			sp.Kind = SourcePathModCache
+		case mainModule != "" && (normalized == mainModule || strings.HasPrefix(normalized, mainModule+"/")):
+			sp.Kind = SourcePathWorkspace
+		case IsStdLib(first):
+			sp.Kind = SourcePathGoroot
+		default:
+			sp.Kind = SourcePathWorkspace
+		}
+		return sp
+	}
+
+	switch {
+	case goroot != "" && strings.HasPrefix(normalized, goroot+"/src/"):
+		sp.Kind = SourcePathGoroot
+	case strings.Contains(normalized, "/pkg/mod/") && strings.Contains(normalized[strings.Index(normalized, "/pkg/mod/"):], "@"):
+		sp.Kind = SourcePathModCache
+	case strings.Contains(normalized, "/vendor/"):
+		sp.Kind = SourcePathVendor
+	default:
+		sp.Kind = SourcePathWorkspace
+	}
+	return sp
+}
+
+// moduleVersionPrefix 返回 -trimpath 相对路径中直到第一个包含"@"的元素为止的部分，
+// 例如 "github.com/spf13/cobra@v1.8.0"，没有版本时返回空字符串
+func moduleVersionPrefix(file string) string {
+	if i := strings.Index(file, "@"); i >= 0 {
+		if end := strings.IndexByte(file[i:], '/'); end >= 0 {
+			return file[:i+end]
+		}
+	}
+	return ""
+}
+
+// detectGoroot 根据 runtime/proc.go 的位置检测构建时的GOROOT目录，路径为相对路径时返回空字符串。
+// 通过 GOTOOLCHAIN 下载的工具链位于模块缓存中，例如 ~/go/pkg/mod/golang.org/toolchain@v0.0.1-go1.22.3.linux-amd64。
+func detectGoroot(files []string) string {
+	for _, file := range files {
+		normalized := normalizeSourcePath(file)
+		if isAbsSourcePath(normalized) && strings.HasSuffix(normalized, "/src/runtime/proc.go") {
+			return strings.TrimSuffix(normalized, "/src/runtime/proc.go")
+		}
+	}
+	return ""
+}
+
+// normalizeSourcePath 将Windows路径中的反斜杠替换为正斜杠
+func normalizeSourcePath(file string) string {
+	return strings.ReplaceAll(file, "\\", "/")
+}
+
+// windowsDrive 匹配Windows绝对路径的盘符，例如 "C:/"
+var windowsDrive = regexp.MustCompile(`^[A-Za-z]:/`)
+
+// isAbsSourcePath 判断规范化后的路径是否为Unix、Windows或UNC绝对路径
+func isAbsSourcePath(normalized string) bool {
+	return strings.HasPrefix(normalized, "/") || windowsDrive.MatchString(normalized)
+}
+
+// workspaceRoots 将工作目录中的源文件按路径的前两个元素（例如 /home/alice）分组，
+// 返回每组共同的最长目录及其文件数量，使位于不同位置的本地替换依赖作为单独的根目录报告
+func workspaceRoots(files []string) map[string]int {
+	groups := make(map[string][]string)
+	for _, file := range files {
+		parts := strings.SplitN(file, "/", 4)
+		anchor := strings.Join(parts[:len(parts)-1], "/")
+		groups[anchor] = append(groups[anchor], file)
+	}
+	roots := make(map[string]int)
+	for _, group := range groups {
+		roots[commonDir(group)] += len(group)
+	}
+	return roots
+}
+
+// commonDir 返回一组绝对路径共同的最长目录。
+// 不使用 path.Dir，因为它会把UNC路径开头的"//"合并为"/"。
+func commonDir(files []string) string {
+	dir := parentDir(files[0])
+	for _, file := range files[1:] {
+		for !strings.HasPrefix(file, dir+"/") && dir != parentDir(dir) {
+			dir = parentDir(dir)
+		}
+	}
+	return dir
+}
+
+// parentDir 返回路径的上一级目录，已经是根目录（"/"、"//"或 "C:"）时返回其本身
+func parentDir(p string) string {
+	i := strings.LastIndexByte(strings.TrimRight(p, "/"), '/')
+	if i <= 0 {
+		if strings.HasPrefix(p, "/") {
+			return "/"
+		}
+		return p
+	}
+	if strings.Trim(p[:i], "/") == "" {
+		return p[:i+1]
+	}
+	return p[:i]
+}
+
+// userHomePatterns 匹配路径中包含用户名的主目录
+var userHomePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^/(?:home|Users|export/home|usr/home|var/home)/([^/]+)/`),
+	regexp.MustCompile(`^[A-Za-z]:/(?:Users|Documents and Settings)/([^/]+)/`),
+	regexp.MustCompile(`^/(root)/`),
+}
+
+// hostnamePatterns 匹配路径中包含主机名的网络路径和构建代理目录
+var hostnamePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^//([^/]+)/`),
+	regexp.MustCompile(`^/net/([^/]+)/`),
+	regexp.MustCompile(`^/(?:var/lib/buildkite-agent|buildkite)/builds/([^/]+)/`),
+}
+
+// ciWorkspaces 是常见CI系统的工作目录前缀及对应的平台名称
+var ciWorkspaces = []struct {
+	pattern *regexp.Regexp
+	name    string
+}{
+	{regexp.MustCompile(`^/(?:home|Users)/runner/work/`), "github-actions"},
+	{regexp.MustCompile(`^[A-Za-z]:/a/`), "github-actions"},
+	{regexp.MustCompile(`^/github/workspace/`), "github-actions"},
+	{regexp.MustCompile(`^/builds/`), "gitlab-ci"},
+	{regexp.MustCompile(`/jenkins(?:_home)?/(?:jobs/[^/]+/)?workspace/`), "jenkins"},
+	{regexp.MustCompile(`^/home/circleci/`), "circleci"},
+	{regexp.MustCompile(`^/home/vsts/work/`), "azure-pipelines"},
+	{regexp.MustCompile(`^/opt/atlassian/pipelines/agent/build/`), "bitbucket-pipelines"},
+	{regexp.MustCompile(`^/drone/src/`), "drone"},
+	{regexp.MustCompile(`^/(?:var/lib/buildkite-agent|buildkite)/builds/`), "buildkite"},
+	{regexp.MustCompile(`^/codebuild/output/`), "aws-codebuild"},
+	{regexp.MustCompile(`^/workspace/`), "cloud-build"},
+}
+
+// findPathLeaks 返回规范化的绝对路径中泄露的用户名、主机名和CI工作目录
+func findPathLeaks(normalized string) []PathLeak {
+	var leaks []PathLeak
+	for _, re := range userHomePatterns {
+		if m := re.FindStringSubmatch(normalized); m != nil {
+			leaks = append(leaks, PathLeak{Kind: PathLeakUsername, Value: m[1]})
+			break
+		}
+	}
+	for _, re := range hostnamePatterns {
+		if m := re.FindStringSubmatch(normalized); m != nil {
+			leaks = append(leaks, PathLeak{Kind: PathLeakHostname, Value: m[1]})
+			break
+		}
+	}
+	for _, ci := range ciWorkspaces {
+		if ci.pattern.MatchString(normalized) {
+			leaks = append(leaks, PathLeak{Kind: PathLeakCIWorkspace, Value: ci.name})
+			break
+		}
+	}
+	return leaks
+}
@@ -0,0 +1,131 @@
+package gobinaryparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeSourcePaths(t *testing.T) {
+	const src = "package main\n\nfunc main() {}\n"
+
+	path := buildTestProgram(t, src, []string{"CGO_ENABLED=0"})
+	report, err := AnalyzeSourcePaths(path)
+	if err != nil {
+		t.Fatalf("AnalyzeSourcePaths() error = %v", err)
+	}
+	if report.Trimmed {
+		t.Error("Trimmed = true for a build without -trimpath")
+	}
+	if report.Counts[SourcePathGoroot] == 0 || report.Counts[SourcePathWorkspace] != 1 {
+		t.Errorf("Counts = %v, want GOROOT files and one workspace file", report.Counts)
+	}
+	var hasGoroot bool
+	for _, root := range report.Roots {
+		hasGoroot = hasGoroot || root.Kind == SourcePathGoroot
+	}
+	if !hasGoroot {
+		t.Errorf("Roots = %+v, want a GOROOT root", report.Roots)
+	}
+
+	path = buildTestProgram(t, src, []string{"CGO_ENABLED=0"}, "-trimpath")
+	report, err = AnalyzeSourcePaths(path)
+	if err != nil {
+		t.Fatalf("AnalyzeSourcePaths() error = %v", err)
+	}
+	if !report.Trimmed || len(report.Roots) != 0 || len(report.Leaks) != 0 {
+		t.Errorf("Report of a -trimpath build = %+v, want trimmed without roots or leaks", report)
+	}
+	for _, file := range report.Files {
+		if file.Path == "example.com/testprog/main.go" && file.Kind != SourcePathWorkspace {
+			t.Errorf("Kind of %s = %q, want %q", file.Path, file.Kind, SourcePathWorkspace)
+		}
+	}
+}
+
+func TestClassifySourcePath(t *testing.T) {
+	const goroot = "/usr/local/go"
+	tests := []struct {
+		path     string
+		kind     SourcePathKind
+		absolute bool
+	}{
+		{"<autogenerated>", SourcePathGenerated, false},
+		{"/usr/local/go/src/runtime/proc.go", SourcePathGoroot, true},
+		{"/home/alice/go/pkg/mod/github.com/spf13/cobra@v1.8.0/command.go", SourcePathModCache, true},
+		{"/home/alice/src/app/vendor/github.com/spf13/cobra/command.go", SourcePathVendor, true},
+		{"/home/alice/src/app/main.go", SourcePathWorkspace, true},
+		{`C:\Users\bob\src\app\main.go`, SourcePathWorkspace, true},
+		{"runtime/proc.go", SourcePathGoroot, false},
+		{"github.com/spf13/cobra@v1.8.0/command.go", SourcePathModCache, false},
+		{"example.com/app/main.go", SourcePathWorkspace, false},
+		{"app/cmd/main.go", SourcePathWorkspace, false},
+	}
+	for _, tt := range tests {
+		got := classifySourcePath(tt.path, goroot, "app")
+		if got.Kind != tt.kind || got.Absolute != tt.absolute {
+			t.Errorf("classifySourcePath(%q) = %+v, want kind %q absolute %t", tt.path, got, tt.kind, tt.absolute)
+		}
+	}
+}
+
+func TestAnalyzeSourcePaths_Leaks(t *testing.T) {
+	files := []string{
+		"/home/runner/go/pkg/mod/golang.org/toolchain@v0.0.1-go1.22.3.linux-amd64/src/runtime/proc.go",
+		"/home/runner/go/pkg/mod/golang.org/toolchain@v0.0.1-go1.22.3.linux-amd64/src/fmt/print.go",
+		"/home/runner/go/pkg/mod/github.com/spf13/cobra@v1.8.0/command.go",
+		"/home/runner/work/app/app/main.go",
+		"/home/runner/work/app/app/internal/x.go",
+		`\\buildhost\share\lib\lib.go`,
+		`C:\Users\bob\src\tool\tool.go`,
+	}
+	report := analyzeSourcePaths(files, "")
+
+	wantCounts := map[SourcePathKind]int{SourcePathGoroot: 2, SourcePathModCache: 1, SourcePathWorkspace: 4}
+	if !reflect.DeepEqual(report.Counts, wantCounts) {
+		t.Errorf("Counts = %v, want %v", report.Counts, wantCounts)
+	}
+	if report.Roots[0].Kind != SourcePathGoroot || report.Roots[0].Path != "/home/runner/go/pkg/mod/golang.org/toolchain@v0.0.1-go1.22.3.linux-amd64" {
+		t.Errorf("Roots[0] = %+v, want the toolchain GOROOT", report.Roots[0])
+	}
+	roots := make(map[string]int)
+	for _, root := range report.Roots {
+		if root.Kind == SourcePathWorkspace {
+			roots[root.Path] = root.Files
+		}
+	}
+	wantRoots := map[string]int{"/home/runner/work/app/app": 2, "//buildhost/share/lib": 1, "C:/Users/bob/src/tool": 1}
+	if !reflect.DeepEqual(roots, wantRoots) {
+		t.Errorf("Workspace roots = %v, want %v", roots, wantRoots)
+	}
+
+	leaks := make(map[PathLeak]int)
+	for _, leak := range report.Leaks {
+		leaks[PathLeak{Kind: leak.Kind, Value: leak.Value}] = leak.Files
+	}
+	want := map[PathLeak]int{
+		{Kind: PathLeakUsername, Value: "runner"}:            5,
+		{Kind: PathLeakCIWorkspace, Value: "github-actions"}: 2,
+		{Kind: PathLeakHostname, Value: "buildhost"}:         1,
+		{Kind: PathLeakUsername, Value: "bob"}:               1,
+	}
+	if !reflect.DeepEqual(leaks, want) {
+		t.Errorf("Leaks = %v, want %v", leaks, want)
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{"/a/b/c.go"}, "/a/b"},
+		{[]string{"/a/b/c.go", "/a/b/d/e.go"}, "/a/b"},
+		{[]string{"/a/b/c.go", "/a/x/e.go"}, "/a"},
+		{[]string{"/a/b.go", "/x/y.go"}, "/"},
+	}
+	for _, tt := range tests {
+		if got := commonDir(tt.files); got != tt.want {
+			t.Errorf("commonDir(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
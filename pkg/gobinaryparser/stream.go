@@ -0,0 +1,208 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// DefaultMaxStreamSize 是从数据流解析时默认允许读取的最大字节数
+	DefaultMaxStreamSize int64 = 2 << 30
+	// DefaultMemoryThreshold 是从数据流解析时默认保存在内存中的最大字节数，超过后写入临时文件
+	DefaultMemoryThreshold int64 = 32 << 20
+)
+
+// StreamOptions 控制 ParseBinaryFromStream 缓存输入数据的方式。
+// 解析构建信息需要随机访问，因此数据流会先被完整读取：较小的输入保存在内存中，
+// 较大的输入写入临时文件，解析完成后临时文件会被删除。
+type StreamOptions struct {
+	// MaxSize 是允许读取的最大字节数，超过时返回 ErrTooLarge，<=0 时使用 DefaultMaxStreamSize
+	MaxSize int64
+	// MemoryThreshold 是保存在内存中的最大字节数，超过时写入临时文件，
+	// 为0时使用 DefaultMemoryThreshold，为负数时总是写入临时文件
+	MemoryThreshold int64
+	// TempDir 是临时文件所在的目录，为空时使用 os.TempDir()
+	TempDir string
+	// SizeHint 是预先知道的数据大小（例如HTTP响应的Content-Length），
+	// 超过MaxSize时不读取任何数据直接返回 ErrTooLarge，<=0 表示未知
+	SizeHint int64
+}
+
+// maxSize 返回生效的最大字节数
+func (o *StreamOptions) maxSize() int64 {
+	if o == nil || o.MaxSize <= 0 {
+		return DefaultMaxStreamSize
+	}
+	return o.MaxSize
+}
+
+// memoryThreshold 返回生效的内存缓存阈值
+func (o *StreamOptions) memoryThreshold() int64 {
+	switch {
+	case o == nil || o.MemoryThreshold == 0:
+		return DefaultMemoryThreshold
+	case o.MemoryThreshold < 0:
+		return 0
+	}
+	return o.MemoryThreshold
+}
+
+// ParseBinaryFromStream 从只能顺序读取的数据流（例如HTTP响应体、管道或压缩包中的文件）解析Go二进制文件。
+// 数据流会按照opts的设置缓存到内存或大小受限的临时文件中，内存占用不会超过MemoryThreshold。
+//
+// 参数:
+//   - ctx: 上下文，取消时停止读取数据流
+//   - r: 二进制文件的数据流
+//   - opts: 缓存选项，为nil时使用默认值
+//
+// 返回:
+//   - *BinaryInfo: 包含二进制文件依赖信息的结构体，SourceType为"stream"
+//   - error: 如果数据超过MaxSize（可以用 errors.Is(err, ErrTooLarge) 判断）、读取失败或解析失败，则返回错误信息
+//
+// 使用示例:
+//
+//	resp, err := http.Get("https://example.com/binaries/kubectl")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer resp.Body.Close()
+//
+//	info, err := gobinaryparser.ParseBinaryFromStream(context.Background(), resp.Body, &gobinaryparser.StreamOptions{
+//		MaxSize:  512 << 20,
+//		SizeHint: resp.ContentLength,
+//	})
+//	if errors.Is(err, gobinaryparser.ErrTooLarge) {
+//		log.Fatal("二进制文件过大")
+//	}
+func ParseBinaryFromStream(ctx context.Context, r io.Reader, opts *StreamOptions) (*BinaryInfo, error) {
+	return defaultParser.ParseBinaryFromStream(ctx, r, opts)
+}
+
+// ParseBinaryFromStream 从数据流解析Go二进制文件，opts没有设置MaxSize时使用解析器的下载大小限制，
+// 参见 ParseBinaryFromStream
+func (p *Parser) ParseBinaryFromStream(ctx context.Context, r io.Reader, opts *StreamOptions) (*BinaryInfo, error) {
+	if opts == nil || opts.MaxSize <= 0 {
+		effective := StreamOptions{}
+		if opts != nil {
+			effective = *opts
+		}
+		effective.MaxSize = p.maxDownloadSize
+		opts = &effective
+	}
+
+	result, err := p.parseStream(ctx, r, opts, "", "stream")
+	if err != nil {
+		return nil, err
+	}
+	p.store("", result)
+	return result, nil
+}
+
+// parseStream 缓存数据流并解析，path和sourceType写入解析结果
+func (p *Parser) parseStream(ctx context.Context, r io.Reader, opts *StreamOptions, path string, sourceType string) (*BinaryInfo, error) {
+	spool, err := spoolStream(ctx, r, opts)
+	if err != nil {
+		return nil, newParseError(path, sourceType, err)
+	}
+	defer spool.Close()
+
+	result, err := p.readBinaryInfo(spool, path, sourceType)
+	if err != nil {
+		return nil, newParseError(path, sourceType, err)
+	}
+	return result, nil
+}
+
+// streamSpool 是数据流的随机访问缓存，数据位于内存或临时文件中
+type streamSpool struct {
+	io.ReaderAt
+	size int64
+	file *os.File
+}
+
+// Close 关闭并删除临时文件
+func (s *streamSpool) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	if rerr := os.Remove(s.file.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// Size 返回缓存的数据的字节数
+func (s *streamSpool) Size() int64 {
+	return s.size
+}
+
+// spoolStream 读取数据流直到结束，不超过阈值时保存在内存中，否则写入临时文件
+func spoolStream(ctx context.Context, r io.Reader, opts *StreamOptions) (*streamSpool, error) {
+	maxSize := opts.maxSize()
+	if opts != nil && opts.SizeHint > maxSize {
+		return nil, fmt.Errorf("%w: 数据大小 %d 字节超过限制 %d 字节", ErrTooLarge, opts.SizeHint, maxSize)
+	}
+
+	cr := &contextReader{ctx: ctx, r: r}
+	threshold := opts.memoryThreshold()
+	if threshold > maxSize {
+		threshold = maxSize
+	}
+
+	// 先读取不超过阈值的数据到内存，多读一个字节用于判断数据是否结束
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(cr, threshold+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取数据流失败: %w", err)
+	}
+	if n <= threshold {
+		return &streamSpool{ReaderAt: bytes.NewReader(buf.Bytes()), size: n}, nil
+	}
+	if n > maxSize {
+		return nil, fmt.Errorf("%w: 数据超过限制 %d 字节", ErrTooLarge, maxSize)
+	}
+
+	tempDir := ""
+	if opts != nil {
+		tempDir = opts.TempDir
+	}
+	f, err := os.CreateTemp(tempDir, "gobinaryparser-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	spool := &streamSpool{ReaderAt: f, file: f}
+
+	if _, err := buf.WriteTo(f); err != nil {
+		spool.Close()
+		return nil, fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	rest, err := io.Copy(f, io.LimitReader(cr, maxSize-n+1))
+	if err != nil {
+		spool.Close()
+		return nil, fmt.Errorf("读取数据流失败: %w", err)
+	}
+	spool.size = n + rest
+	if spool.size > maxSize {
+		spool.Close()
+		return nil, fmt.Errorf("%w: 数据超过限制 %d 字节", ErrTooLarge, maxSize)
+	}
+	return spool, nil
+}
+
+// contextReader 在每次读取前检查上下文是否已取消
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read 实现io.Reader接口
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,131 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func readTestBinary(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test binary: %v", err)
+	}
+	return data
+}
+
+func TestParseBinaryFromStream(t *testing.T) {
+	data := readTestBinary(t)
+
+	tests := []struct {
+		name      string
+		threshold int64
+	}{
+		{"memory", int64(len(data))},
+		{"temp file", -1},
+		{"partial memory", 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			info, err := ParseBinaryFromStream(context.Background(), bytes.NewReader(data), &StreamOptions{
+				MemoryThreshold: tt.threshold,
+				TempDir:         dir,
+			})
+			if err != nil {
+				t.Fatalf("ParseBinaryFromStream() error = %v", err)
+			}
+			if info.GoVersion != runtime.Version() {
+				t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+			}
+			if info.SourceType != "stream" {
+				t.Errorf("SourceType = %q, want %q", info.SourceType, "stream")
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("ReadDir() error = %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("Temporary files were not removed: %v", entries)
+			}
+		})
+	}
+}
+
+func TestParseBinaryFromStream_TooLarge(t *testing.T) {
+	data := readTestBinary(t)
+
+	tests := []struct {
+		name string
+		opts *StreamOptions
+	}{
+		{"in memory", &StreamOptions{MaxSize: 1024}},
+		{"in temp file", &StreamOptions{MaxSize: int64(len(data)) - 1, MemoryThreshold: 4096}},
+		{"size hint", &StreamOptions{MaxSize: 1024, SizeHint: int64(len(data))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.TempDir = t.TempDir()
+			_, err := ParseBinaryFromStream(context.Background(), bytes.NewReader(data), tt.opts)
+			if !errors.Is(err, ErrTooLarge) {
+				t.Fatalf("ParseBinaryFromStream() error = %v, want ErrTooLarge", err)
+			}
+
+			entries, _ := os.ReadDir(tt.opts.TempDir)
+			if len(entries) != 0 {
+				t.Errorf("Temporary files were not removed: %v", entries)
+			}
+		})
+	}
+
+	// 恰好等于上限时可以解析
+	if _, err := ParseBinaryFromStream(context.Background(), bytes.NewReader(data), &StreamOptions{
+		MaxSize:         int64(len(data)),
+		MemoryThreshold: 4096,
+		TempDir:         t.TempDir(),
+	}); err != nil {
+		t.Errorf("ParseBinaryFromStream() at exact limit error = %v", err)
+	}
+}
+
+func TestParseBinaryFromStream_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseBinaryFromStream(ctx, bytes.NewReader(readTestBinary(t)), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseBinaryFromStream() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseBinaryFromURL_Stream(t *testing.T) {
+	data := readTestBinary(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	info, err := ParseBinaryFromURL(server.URL)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromURL() error = %v", err)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+	if info.SourceType != "url" || info.FilePath != server.URL {
+		t.Errorf("SourceType = %q, FilePath = %q, want url source", info.SourceType, info.FilePath)
+	}
+}
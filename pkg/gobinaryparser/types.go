@@ -1,7 +1,10 @@
 // Package gobinaryparser 提供用于解析Go二进制文件依赖关系的功能。
-// 该包可以分析任何使用Go 1.12+编译并包含构建信息的二进制文件。
+// 该包可以分析任何使用Go 1.12+编译并包含构建信息的二进制文件，
+// 对于缺少构建信息的二进制文件，会以降级模式从符号表和pclntab中推断依赖。
 package gobinaryparser
 
+import "time"
+
 // DependencyInfo 表示Go二进制文件中的一个依赖信息
 // 示例：
 //
@@ -20,6 +23,36 @@ type DependencyInfo struct {
 	Version string          `json:"version"`           // 依赖的版本，例如 "v1.6.1"
 	Sum     string          `json:"sum,omitempty"`     // 依赖的校验和，例如 "h1:xJqmnvzCeeF2MXGd8Byi93jN5wLSQOkImGTD2MMpcL0="
 	Replace *DependencyInfo `json:"replace,omitempty"` // 替换信息，如果此依赖被替换则不为nil
+
+	// Confidence 表示依赖信息的可信度，仅在降级模式下从源码路径推断依赖时设置
+	Confidence Confidence `json:"confidence,omitempty"`
+}
+
+// Confidence 表示在降级模式下推断出的信息的可信度
+type Confidence string
+
+const (
+	// ConfidenceHigh 表示信息可以直接确定，例如来自模块缓存路径中的模块路径和版本
+	ConfidenceHigh Confidence = "high"
+	// ConfidenceMedium 表示模块路径是推断的，但确认被编译进了二进制文件，例如vendor目录中的包
+	ConfidenceMedium Confidence = "medium"
+	// ConfidenceLow 表示模块路径和版本都是推测的，例如从GOPATH中的导入路径推断
+	ConfidenceLow Confidence = "low"
+)
+
+// DegradedInfo 描述在二进制文件缺少构建信息时通过启发式方法恢复信息的过程。
+// 没有构建信息的二进制文件包括Go 1.13之前编译的、构建信息被剥离的二进制文件等。
+// 示例：
+//
+//	{
+//	  "reason": "not a Go executable",
+//	  "go_version_source": "symbol",
+//	  "go_version_confidence": "high"
+//	}
+type DegradedInfo struct {
+	Reason              string     `json:"reason"`                          // 读取构建信息失败的原因
+	GoVersionSource     string     `json:"go_version_source,omitempty"`     // Go版本的来源："symbol"（runtime.buildVersion）或"scan"（扫描只读数据）
+	GoVersionConfidence Confidence `json:"go_version_confidence,omitempty"` // Go版本的可信度，未能恢复Go版本时为空
 }
 
 // BinaryInfo 表示从Go二进制文件中解析出的信息
@@ -31,15 +64,295 @@ type DependencyInfo struct {
 //	  "dependencies": [...],
 //	  "go_version": "go1.18.2",
 //	  "build_settings": {"GOOS": "linux", "GOARCH": "amd64"},
+//	  "build_config": {"goos": "linux", "goarch": "amd64", ...},
 //	  "file_path": "/usr/local/bin/myapp",
-//	  "source_type": "file"
+//	  "source_type": "file",
+//	  "format": {"type": "elf", "arch": "amd64", ...},
+//	  "build_id": {"id": "abc/def/ghi/jkl", "action_id": "abc", "content_id": "jkl"}
 //	}
 type BinaryInfo struct {
-	Path          string            `json:"path"`           // 主模块路径，例如 "github.com/example/myapp"
-	Version       string            `json:"version"`        // 主模块版本，例如 "v1.0.0"
-	Dependencies  []DependencyInfo  `json:"dependencies"`   // 依赖列表
-	GoVersion     string            `json:"go_version"`     // 编译使用的Go版本，例如 "go1.18.2"
-	BuildSettings map[string]string `json:"build_settings"` // 编译设置，包含GOOS、GOARCH等
-	FilePath      string            `json:"file_path"`      // 解析的二进制文件路径，对于非文件源可能为空
-	SourceType    string            `json:"source_type"`    // 源类型（"file"、"url"、"bytes"、"reader"）
+	Path          string            `json:"path"`                     // 主模块路径，例如 "github.com/example/myapp"
+	Version       string            `json:"version"`                  // 主模块版本，例如 "v1.0.0"
+	Dependencies  []DependencyInfo  `json:"dependencies"`             // 依赖列表
+	GoVersion     string            `json:"go_version"`               // 编译使用的Go版本，例如 "go1.18.2"
+	BuildSettings map[string]string `json:"build_settings"`           // 编译设置，包含GOOS、GOARCH等
+	BuildConfig   *BuildConfig      `json:"build_config"`             // 从BuildSettings解析出的结构化编译配置
+	FilePath      string            `json:"file_path"`                // 解析的二进制文件路径，对于非文件源可能为空
+	SourceType    string            `json:"source_type"`              // 源类型（"file"、"url"、"bytes"、"reader"、"stream"）
+	Format        *FormatInfo       `json:"format,omitempty"`         // 可执行文件格式信息，无法识别格式时为nil
+	BuildID       *GoBuildID        `json:"build_id,omitempty"`       // Go构建ID，二进制文件中没有构建ID时为nil
+	Degraded      *DegradedInfo     `json:"degraded,omitempty"`       // 降级模式信息，非nil表示构建信息缺失，结果由启发式方法恢复
+	BuildMode     string            `json:"build_mode,omitempty"`     // 构建模式，例如 "exe"、"pie"、"c-shared"、"c-archive"、"plugin"
+	ArchiveMember string            `json:"archive_member,omitempty"` // 对于静态库（c-archive），包含Go构建信息的成员文件名，例如 "go.o"
+	Nested        []NestedBinary    `json:"nested,omitempty"`         // 深度扫描找到的嵌入的Go二进制文件，只在启用 WithDeepScan 时设置
+	RawBuildInfo  *RawBuildInfo     `json:"raw_build_info,omitempty"` // 构建信息的原始数据及其在文件中的偏移，降级模式下为nil
+	RemoteStats   *HTTPReaderStats  `json:"remote_stats,omitempty"`   // 范围请求的统计，只在 ParseBinaryFromRemoteFile 的结果中设置
+
+	modulePath string // 构建信息中记录的主模块路径，体积归属和生成go.mod时用于区分主模块
+}
+
+// 构建模式，与 go build -buildmode 的取值一致
+const (
+	BuildModeExe      = "exe"       // 普通可执行文件
+	BuildModePIE      = "pie"       // 位置无关可执行文件
+	BuildModeCShared  = "c-shared"  // 供C程序使用的共享库
+	BuildModeCArchive = "c-archive" // 供C程序使用的静态库
+	BuildModePlugin   = "plugin"    // Go插件
+)
+
+// VCSInfo 表示编译时记录的版本控制信息
+// 示例：
+//
+//	{
+//	  "system": "git",
+//	  "revision": "a7f686d8f418f7a3d4f8d2e0c1b5e6d7c8f9a0b1",
+//	  "time": "2023-05-01T12:00:00Z",
+//	  "modified": false
+//	}
+type VCSInfo struct {
+	System   string    `json:"system"`             // 版本控制系统，例如 "git"
+	Revision string    `json:"revision,omitempty"` // 提交修订号，对应 vcs.revision
+	Time     time.Time `json:"time"`               // 提交时间，对应 vcs.time，无法解析时为零值
+	Modified bool      `json:"modified"`           // 工作区是否存在未提交的修改，对应 vcs.modified
+}
+
+// BuildConfig 表示从构建设置中解析出的结构化编译配置。
+// 原始的键值对仍然保存在 BinaryInfo.BuildSettings 中，此结构体只是对常用设置的类型化视图。
+// 示例：
+//
+//	{
+//	  "goos": "linux",
+//	  "goarch": "amd64",
+//	  "arch_features": {"GOAMD64": "v3"},
+//	  "compiler": "gc",
+//	  "build_mode": "exe",
+//	  "cgo_enabled": true,
+//	  "tags": ["netgo", "osusergo"],
+//	  "ldflags": ["-s", "-w", "-X", "main.version=v1.0.0"],
+//	  "trimpath": true,
+//	  "experiments": ["boringcrypto"],
+//	  "vcs": {"system": "git", "revision": "a7f686d8f418", ...}
+//	}
+type BuildConfig struct {
+	GOOS           string            `json:"goos,omitempty"`            // 目标操作系统
+	GOARCH         string            `json:"goarch,omitempty"`          // 目标架构
+	ArchFeatures   map[string]string `json:"arch_features,omitempty"`   // 架构相关的特性级别，例如 GOAMD64、GOARM
+	Compiler       string            `json:"compiler,omitempty"`        // 使用的编译器，对应 -compiler，例如 "gc"
+	BuildMode      string            `json:"build_mode,omitempty"`      // 构建模式，对应 -buildmode，例如 "exe"、"pie"
+	CGOEnabled     bool              `json:"cgo_enabled"`               // 是否启用了cgo，对应 CGO_ENABLED
+	CGOFlags       map[string]string `json:"cgo_flags,omitempty"`       // cgo相关的编译标志，例如 CGO_CFLAGS、CGO_LDFLAGS
+	Tags           []string          `json:"tags,omitempty"`            // 构建标签，对应 -tags
+	LDFlags        []string          `json:"ldflags,omitempty"`         // 链接器参数，对应 -ldflags，已按shell引号规则拆分
+	GCFlags        []string          `json:"gcflags,omitempty"`         // 编译器参数，对应 -gcflags
+	ASMFlags       []string          `json:"asmflags,omitempty"`        // 汇编器参数，对应 -asmflags
+	TrimPath       bool              `json:"trimpath"`                  // 是否使用了 -trimpath
+	Race           bool              `json:"race,omitempty"`            // 是否启用了竞态检测，对应 -race
+	MSan           bool              `json:"msan,omitempty"`            // 是否启用了内存检测，对应 -msan
+	ASan           bool              `json:"asan,omitempty"`            // 是否启用了地址检测，对应 -asan
+	PGO            string            `json:"pgo,omitempty"`             // 使用的PGO配置文件，对应 -pgo
+	Experiments    []string          `json:"experiments,omitempty"`     // 启用的实验特性，对应 GOEXPERIMENT
+	DefaultGODEBUG string            `json:"default_godebug,omitempty"` // 默认的GODEBUG设置，对应 DefaultGODEBUG
+	VCS            *VCSInfo          `json:"vcs,omitempty"`             // 版本控制信息，未记录时为nil
+}
+
+// PackageInfo 表示链接进Go二进制文件的一个包
+// 示例：
+//
+//	{
+//	  "path": "net/http",
+//	  "functions": 1523,
+//	  "stdlib": true
+//	}
+type PackageInfo struct {
+	Path      string `json:"path"`      // 包的导入路径，例如 "net/http"
+	Functions int    `json:"functions"` // 该包链接进二进制文件的函数数量
+	StdLib    bool   `json:"stdlib"`    // 是否为标准库包
+}
+
+// SizeBucketKind 表示体积归属分组的类型
+type SizeBucketKind string
+
+// 体积归属分组的类型
+const (
+	SizeBucketMain    SizeBucketKind = "main"    // 主模块
+	SizeBucketModule  SizeBucketKind = "module"  // 依赖模块
+	SizeBucketStdLib  SizeBucketKind = "stdlib"  // 标准库（不含runtime）
+	SizeBucketRuntime SizeBucketKind = "runtime" // Go运行时
+	SizeBucketCgo     SizeBucketKind = "cgo"     // 通过cgo链接的C代码和数据
+	SizeBucketOther   SizeBucketKind = "other"   // 编译器生成的元数据等无法归属的内容
+)
+
+// ModuleSize 表示归属于某个模块或分组的二进制体积
+// 示例：
+//
+//	{
+//	  "path": "github.com/spf13/cobra",
+//	  "version": "v1.6.1",
+//	  "kind": "module",
+//	  "text_bytes": 245760,
+//	  "rodata_bytes": 51200,
+//	  "data_bytes": 1024,
+//	  "percent": 2.35
+//	}
+type ModuleSize struct {
+	Path        string         `json:"path"`              // 模块路径，或者分组名称（"stdlib"、"runtime"、"cgo"、"other"）
+	Version     string         `json:"version,omitempty"` // 模块版本，分组没有版本
+	Kind        SizeBucketKind `json:"kind"`              // 分组类型
+	TextBytes   uint64         `json:"text_bytes"`        // 代码段字节数
+	RodataBytes uint64         `json:"rodata_bytes"`      // 只读数据段字节数
+	DataBytes   uint64         `json:"data_bytes"`        // 可写数据段字节数（不含bss）
+	Percent     float64        `json:"percent"`           // 占所有已归属字节的百分比
+}
+
+// Total 返回该模块归属的总字节数
+func (m ModuleSize) Total() uint64 {
+	return m.TextBytes + m.RodataBytes + m.DataBytes
+}
+
+// SizeReport 表示Go二进制文件的体积归属分析结果
+// 示例：
+//
+//	{
+//	  "file_size": 83886080,
+//	  "text_size": 35651584,
+//	  "rodata_size": 31457280,
+//	  "data_size": 1048576,
+//	  "attributed_size": 60817408,
+//	  "has_symbols": true,
+//	  "modules": [...]
+//	}
+type SizeReport struct {
+	FileSize       int64        `json:"file_size,omitempty"` // 文件大小，从非文件源分析时为0
+	TextSize       uint64       `json:"text_size"`           // 所有代码段的总大小
+	RodataSize     uint64       `json:"rodata_size"`         // 所有只读数据段的总大小
+	DataSize       uint64       `json:"data_size"`           // 所有可写数据段的总大小（不含bss）
+	AttributedSize uint64       `json:"attributed_size"`     // 成功归属到模块或分组的总字节数
+	HasSymbols     bool         `json:"has_symbols"`         // 是否有符号表，没有符号表时只能归属代码段
+	Modules        []ModuleSize `json:"modules"`             // 按总字节数降序排列的归属结果
+}
+
+// 可执行文件格式类型
+const (
+	FormatELF   = "elf"   // Linux、BSD等系统使用的ELF格式
+	FormatPE    = "pe"    // Windows使用的PE格式
+	FormatMachO = "macho" // macOS使用的Mach-O格式
+	FormatWasm  = "wasm"  // WebAssembly模块
+)
+
+// FormatInfo 表示Go二进制文件的容器格式信息
+// 示例：
+//
+//	{
+//	  "type": "elf",
+//	  "arch": "amd64",
+//	  "bits": 64,
+//	  "endian": "little",
+//	  "symbols_stripped": false,
+//	  "dwarf_stripped": true,
+//	  "pie": false,
+//	  "static": false,
+//	  "interpreter": "/lib64/ld-linux-x86-64.so.2",
+//	  "libraries": ["libc.so.6"]
+//	}
+type FormatInfo struct {
+	Type            string   `json:"type"`                  // 文件格式，取值为 FormatELF、FormatPE、FormatMachO 或 FormatWasm
+	Arch            string   `json:"arch"`                  // 目标架构，使用GOARCH命名，例如 "amd64"、"arm64"
+	Bits            int      `json:"bits"`                  // 字长，32或64
+	Endian          string   `json:"endian"`                // 字节序，"little" 或 "big"
+	SymbolsStripped bool     `json:"symbols_stripped"`      // 符号表是否已被剥离（例如使用了 -ldflags=-s）
+	DWARFStripped   bool     `json:"dwarf_stripped"`        // DWARF调试信息是否已被剥离（例如使用了 -ldflags=-w）
+	PIE             bool     `json:"pie"`                   // 是否为位置无关可执行文件
+	Shared          bool     `json:"shared"`                // 是否为共享库（.so、.dylib或.dll）
+	Static          bool     `json:"static"`                // 是否为静态链接（不依赖动态链接器和共享库）
+	Interpreter     string   `json:"interpreter,omitempty"` // 动态链接器路径，例如 "/lib64/ld-linux-x86-64.so.2"
+	Libraries       []string `json:"libraries,omitempty"`   // 依赖的动态库（DT_NEEDED、PE导入表或LC_LOAD_DYLIB）
+}
+
+// GoBuildID 表示Go链接器写入二进制文件的构建ID
+// 示例：
+//
+//	{
+//	  "id": "Kc8ugOSyiO7ehrnW_9Zz/2Z6ZYBMGbqS86mCUhf6S/ERGZnYbOj6MMxhVBR7ep/ZWk0FOKL1rbhqxtJTzdw",
+//	  "action_id": "Kc8ugOSyiO7ehrnW_9Zz",
+//	  "content_id": "ZWk0FOKL1rbhqxtJTzdw"
+//	}
+type GoBuildID struct {
+	ID        string `json:"id"`                   // 完整的构建ID，与 go tool buildid 的输出一致
+	ActionID  string `json:"action_id,omitempty"`  // 构建动作的哈希，对应Go构建缓存中的action ID
+	ContentID string `json:"content_id,omitempty"` // 二进制内容的哈希，相同内容的构建具有相同的content ID
+}
+
+// GoVersion 表示解析后的Go工具链版本，由 ParseGoVersion 从 runtime.Version() 格式的字符串解析得到
+// 示例（对应 "go1.22.3 X:boringcrypto"）：
+//
+//	{
+//	  "raw": "go1.22.3 X:boringcrypto",
+//	  "major": 1,
+//	  "minor": 22,
+//	  "patch": 3,
+//	  "experiments": ["boringcrypto"]
+//	}
+type GoVersion struct {
+	Raw         string   `json:"raw"`                    // 原始版本字符串
+	Major       int      `json:"major"`                  // 主版本号，旧式开发版本（"devel +abc123"）为0
+	Minor       int      `json:"minor"`                  // 次版本号
+	Patch       int      `json:"patch"`                  // 修订号
+	Prerelease  string   `json:"prerelease,omitempty"`   // 预发布标识，例如 "rc2"、"beta1"
+	Devel       bool     `json:"devel,omitempty"`        // 是否为从源码构建的开发版本
+	DevelCommit string   `json:"devel_commit,omitempty"` // 开发版本的提交哈希
+	Experiments []string `json:"experiments,omitempty"`  // 版本字符串中 X: 之后列出的GOEXPERIMENT
+}
+
+// UniversalBinaryInfo 表示从Mach-O通用二进制文件（fat binary）中解析出的各架构切片的信息
+// 示例：
+//
+//	{
+//	  "file_path": "/Applications/MyApp.app/Contents/MacOS/myapp",
+//	  "slices": [
+//	    {"arch": "amd64", "offset": 16384, "size": 5242880, "info": {...}},
+//	    {"arch": "arm64", "offset": 5259264, "size": 5111808, "info": {...}}
+//	  ],
+//	  "consistent": false,
+//	  "mismatches": [
+//	    {"field": "go_version", "values": {"amd64": "go1.21.5", "arm64": "go1.21.6"}}
+//	  ]
+//	}
+type UniversalBinaryInfo struct {
+	FilePath   string           `json:"file_path"`            // 解析的二进制文件路径，对于非文件源可能为空
+	SourceType string           `json:"source_type"`          // 源类型（"file"、"reader"）
+	Slices     []UniversalSlice `json:"slices"`               // 各架构切片，顺序与文件头中的记录一致
+	Consistent bool             `json:"consistent"`           // 所有成功解析的切片的Go版本、主模块和依赖是否一致
+	Mismatches []SliceMismatch  `json:"mismatches,omitempty"` // 切片之间的差异
+}
+
+// UniversalSlice 表示通用二进制文件中的一个架构切片
+type UniversalSlice struct {
+	Arch   string      `json:"arch"`            // 架构名称，例如 "amd64"、"arm64"
+	Offset uint64      `json:"offset"`          // 切片在文件中的偏移
+	Size   uint64      `json:"size"`            // 切片的字节数
+	Info   *BinaryInfo `json:"info,omitempty"`  // 切片的解析结果，解析失败时为nil
+	Error  string      `json:"error,omitempty"` // 切片解析失败的原因
+}
+
+// MismatchField 表示切片之间存在差异的字段
+type MismatchField string
+
+const (
+	MismatchGoVersion  MismatchField = "go_version"  // Go版本不同
+	MismatchMainModule MismatchField = "main_module" // 主模块路径或版本不同
+	MismatchDependency MismatchField = "dependency"  // 依赖的版本或替换不同，或只存在于部分切片中
+)
+
+// SliceMismatch 表示通用二进制文件的切片之间的一处差异
+// 示例：
+//
+//	{
+//	  "field": "dependency",
+//	  "path": "golang.org/x/sys",
+//	  "values": {"amd64": "v0.15.0", "arm64": ""}
+//	}
+type SliceMismatch struct {
+	Field  MismatchField     `json:"field"`          // 存在差异的字段
+	Path   string            `json:"path,omitempty"` // 存在差异的依赖路径，仅当Field为dependency时设置
+	Values map[string]string `json:"values"`         // 各架构对应的值，依赖不存在于某个切片时值为空字符串
 }
@@ -0,0 +1,205 @@
+package gobinaryparser
+
+import (
+	"debug/macho"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ParseUniversalBinary 解析Mach-O通用二进制文件（fat binary）中的每个架构切片，
+// 并比较各切片的Go版本、主模块和依赖是否一致。
+// 对通用二进制文件调用 ParseBinary 只会得到其中一个切片的结果。
+//
+// 参数:
+//   - filePath: 通用二进制文件的路径
+//
+// 返回:
+//   - *UniversalBinaryInfo: 每个架构切片的解析结果以及切片之间的差异
+//   - error: 如果文件不是通用二进制文件或无法读取，则返回错误信息；单个切片解析失败记录在切片的Error字段中
+//
+// 使用示例:
+//
+//	universal, err := gobinaryparser.ParseUniversalBinary("/usr/local/bin/myapp")
+//	if err != nil {
+//		log.Fatalf("解析通用二进制文件失败: %v", err)
+//	}
+//	for _, slice := range universal.Slices {
+//		if slice.Info != nil {
+//			fmt.Printf("%s: %s, %d个依赖\n", slice.Arch, slice.Info.GoVersion, len(slice.Info.Dependencies))
+//		}
+//	}
+//	if !universal.Consistent {
+//		fmt.Println("各架构切片的依赖不一致")
+//	}
+func ParseUniversalBinary(filePath string) (*UniversalBinaryInfo, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, newParseError(filePath, "file", fmt.Errorf("获取绝对路径失败: %w", err))
+	}
+
+	f, err := os.Open(absPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return nil, newParseError(absPath, "file", err)
+	}
+	defer f.Close()
+
+	return parseUniversal(f, absPath, "file")
+}
+
+// ParseUniversalBinaryFromReader 从io.ReaderAt接口解析Mach-O通用二进制文件中的每个架构切片。
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - *UniversalBinaryInfo: 每个架构切片的解析结果以及切片之间的差异
+//   - error: 如果数据不是通用二进制文件，则返回错误信息
+//
+// 使用示例:
+//
+//	data, _ := os.ReadFile("/usr/local/bin/myapp")
+//	universal, err := gobinaryparser.ParseUniversalBinaryFromReader(bytes.NewReader(data))
+//	if err != nil {
+//		log.Fatalf("解析通用二进制文件失败: %v", err)
+//	}
+//	fmt.Printf("切片数量: %d\n", len(universal.Slices))
+func ParseUniversalBinaryFromReader(r io.ReaderAt) (*UniversalBinaryInfo, error) {
+	return parseUniversal(r, "", "reader")
+}
+
+// IsUniversalBinary 判断数据是否为Mach-O通用二进制文件。
+// 通用二进制文件与Java class文件使用相同的魔数，因此还会校验文件头中的架构记录。
+//
+// 参数:
+//   - r: 实现了io.ReaderAt接口的对象，用于读取二进制数据
+//
+// 返回:
+//   - bool: 如果是通用二进制文件则返回true
+//
+// 使用示例:
+//
+//	f, _ := os.Open("/usr/local/bin/myapp")
+//	defer f.Close()
+//	if gobinaryparser.IsUniversalBinary(f) {
+//		universal, _ := gobinaryparser.ParseUniversalBinaryFromReader(f)
+//		fmt.Printf("切片数量: %d\n", len(universal.Slices))
+//	}
+func IsUniversalBinary(r io.ReaderAt) bool {
+	_, err := macho.NewFatFile(r)
+	return err == nil
+}
+
+// parseUniversal 解析通用二进制文件的每个切片并比较它们
+func parseUniversal(r io.ReaderAt, path string, sourceType string) (*UniversalBinaryInfo, error) {
+	fat, err := macho.NewFatFile(r)
+	if err != nil {
+		if errors.Is(err, macho.ErrNotFat) {
+			return nil, newParseError(path, sourceType, fmt.Errorf("%w: 不是Mach-O通用二进制文件", ErrUnsupportedFormat))
+		}
+		return nil, newParseError(path, sourceType, fmt.Errorf("%w: 读取通用二进制文件头失败: %w", ErrUnsupportedFormat, err))
+	}
+
+	result := &UniversalBinaryInfo{
+		FilePath:   path,
+		SourceType: sourceType,
+		Slices:     make([]UniversalSlice, 0, len(fat.Arches)),
+	}
+
+	seen := make(map[string]bool)
+	for i, arch := range fat.Arches {
+		slice := UniversalSlice{
+			Arch:   machoArch(arch.Cpu),
+			Offset: uint64(arch.Offset),
+			Size:   uint64(arch.Size),
+		}
+		// 同一架构的不同子类型可能同时存在，用序号区分
+		if seen[slice.Arch] {
+			slice.Arch = fmt.Sprintf("%s#%d", slice.Arch, i)
+		}
+		seen[slice.Arch] = true
+
+		sr := io.NewSectionReader(r, int64(arch.Offset), int64(arch.Size))
+		info, err := readBinaryInfo(sr, path, sourceType)
+		if err != nil {
+			slice.Error = err.Error()
+		} else {
+			info.RawBuildInfo.shift(int64(arch.Offset))
+			slice.Info = info
+		}
+		result.Slices = append(result.Slices, slice)
+	}
+
+	result.Mismatches = compareSlices(result.Slices)
+	result.Consistent = len(result.Mismatches) == 0
+	return result, nil
+}
+
+// compareSlices 比较成功解析的切片之间的Go版本、主模块和依赖
+func compareSlices(slices []UniversalSlice) []SliceMismatch {
+	var parsed []UniversalSlice
+	for _, slice := range slices {
+		if slice.Info != nil {
+			parsed = append(parsed, slice)
+		}
+	}
+	if len(parsed) < 2 {
+		return nil
+	}
+
+	var mismatches []SliceMismatch
+	check := func(field MismatchField, path string, value func(*BinaryInfo) string) {
+		values := make(map[string]string, len(parsed))
+		differ := false
+		for _, slice := range parsed {
+			values[slice.Arch] = value(slice.Info)
+			if values[slice.Arch] != values[parsed[0].Arch] {
+				differ = true
+			}
+		}
+		if differ {
+			mismatches = append(mismatches, SliceMismatch{Field: field, Path: path, Values: values})
+		}
+	}
+
+	check(MismatchGoVersion, "", func(info *BinaryInfo) string {
+		return info.GoVersion
+	})
+	check(MismatchMainModule, "", func(info *BinaryInfo) string {
+		return info.Path + "@" + info.Version
+	})
+
+	paths := make(map[string]bool)
+	for _, slice := range parsed {
+		for _, dep := range slice.Info.Dependencies {
+			paths[dep.Path] = true
+		}
+	}
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+
+	for _, path := range sortedPaths {
+		check(MismatchDependency, path, func(info *BinaryInfo) string {
+			dep := info.GetDependencyByPath(path)
+			if dep == nil {
+				return ""
+			}
+			if dep.Replace != nil {
+				return fmt.Sprintf("%s => %s@%s", dep.Version, dep.Replace.Path, dep.Replace.Version)
+			}
+			return dep.Version
+		})
+	}
+
+	return mismatches
+}
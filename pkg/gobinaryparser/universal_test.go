@@ -0,0 +1,156 @@
+package gobinaryparser
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// buildDarwinBinary 交叉编译一个最小的macOS程序，用于构造通用二进制文件
+func buildDarwinBinary(t *testing.T, goarch string) []byte {
+	t.Helper()
+
+	out := buildTestProgram(t, "package main\n\nfunc main() {}\n",
+		[]string{"GOOS=darwin", "GOARCH=" + goarch, "CGO_ENABLED=0"})
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read built binary: %v", err)
+	}
+	return data
+}
+
+// buildFatBinary 将多个切片拼接为Mach-O通用二进制文件，切片按4KB对齐
+func buildFatBinary(cpus []macho.Cpu, slices [][]byte) []byte {
+	const align = 1 << 12
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, []uint32{macho.MagicFat, uint32(len(slices))})
+
+	offset := uint32(align)
+	for i, slice := range slices {
+		binary.Write(&buf, binary.BigEndian, []uint32{uint32(cpus[i]), 0, offset, uint32(len(slice)), 12})
+		offset += (uint32(len(slice)) + align - 1) &^ (align - 1)
+	}
+	for _, slice := range slices {
+		buf.Write(make([]byte, int(alignUp(uint64(buf.Len()), align))-buf.Len()))
+		buf.Write(slice)
+	}
+	return buf.Bytes()
+}
+
+func TestParseUniversalBinaryFromReader(t *testing.T) {
+	amd64, arm64 := buildDarwinBinary(t, "amd64"), buildDarwinBinary(t, "arm64")
+
+	fat := buildFatBinary([]macho.Cpu{macho.CpuAmd64, macho.CpuArm64}, [][]byte{amd64, arm64})
+	if !IsUniversalBinary(bytes.NewReader(fat)) {
+		t.Fatal("IsUniversalBinary() = false, want true")
+	}
+
+	universal, err := ParseUniversalBinaryFromReader(bytes.NewReader(fat))
+	if err != nil {
+		t.Fatalf("ParseUniversalBinaryFromReader() error = %v", err)
+	}
+	if len(universal.Slices) != 2 {
+		t.Fatalf("Expected 2 slices, got %d", len(universal.Slices))
+	}
+	for i, arch := range []string{"amd64", "arm64"} {
+		slice := universal.Slices[i]
+		if slice.Arch != arch {
+			t.Errorf("Slices[%d].Arch = %q, want %q", i, slice.Arch, arch)
+		}
+		if slice.Info == nil {
+			t.Fatalf("Slices[%d] failed to parse: %s", i, slice.Error)
+		}
+		if slice.Info.Format == nil || slice.Info.Format.Arch != arch {
+			t.Errorf("Slices[%d].Info.Format = %+v, want arch %s", i, slice.Info.Format, arch)
+		}
+		if slice.Info.GoVersion != runtime.Version() {
+			t.Errorf("Slices[%d].Info.GoVersion = %q, want %q", i, slice.Info.GoVersion, runtime.Version())
+		}
+	}
+	if !universal.Consistent || len(universal.Mismatches) != 0 {
+		t.Errorf("Expected identical slices to be consistent, got %+v", universal.Mismatches)
+	}
+}
+
+func TestParseUniversalBinaryFromReader_GoVersionMismatch(t *testing.T) {
+	amd64, modified := buildDarwinBinary(t, "amd64"), buildDarwinBinary(t, "arm64")
+
+	// 修改第二个切片中内联存储的Go版本字符串的最后一个字符
+	i := bytes.Index(modified, []byte("\xff Go buildinf:"))
+	version := runtime.Version()
+	if i < 0 || len(version) > 127 || int(modified[i+32]) != len(version) {
+		t.Skip("Test binary does not use the inline build info format")
+	}
+	modified[i+32+len(version)] = 'X'
+
+	fat := buildFatBinary([]macho.Cpu{macho.CpuAmd64, macho.CpuArm64}, [][]byte{amd64, modified})
+	universal, err := ParseUniversalBinaryFromReader(bytes.NewReader(fat))
+	if err != nil {
+		t.Fatalf("ParseUniversalBinaryFromReader() error = %v", err)
+	}
+	if universal.Consistent {
+		t.Fatal("Expected slices with different Go versions to be inconsistent")
+	}
+	mismatch := universal.Mismatches[0]
+	if mismatch.Field != MismatchGoVersion {
+		t.Errorf("Mismatches[0].Field = %q, want %q", mismatch.Field, MismatchGoVersion)
+	}
+	if mismatch.Values["amd64"] != version || mismatch.Values["arm64"] == version {
+		t.Errorf("Unexpected mismatch values: %v", mismatch.Values)
+	}
+}
+
+func TestParseUniversalBinaryFromReader_NotFat(t *testing.T) {
+	data, err := os.ReadFile(testBinaryPath(t))
+	if err != nil {
+		t.Fatalf("Failed to read test binary: %v", err)
+	}
+	if IsUniversalBinary(bytes.NewReader(data)) {
+		t.Error("IsUniversalBinary() = true for a thin binary")
+	}
+	if _, err := ParseUniversalBinaryFromReader(bytes.NewReader(data)); err == nil {
+		t.Error("Expected error for a thin binary")
+	}
+}
+
+func TestCompareSlices(t *testing.T) {
+	amd64 := &BinaryInfo{
+		Path:      "github.com/example/app",
+		Version:   "v1.0.0",
+		GoVersion: "go1.21.5",
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0"},
+			{Path: "golang.org/x/sys", Version: "v0.15.0"},
+		},
+	}
+	arm64 := &BinaryInfo{
+		Path:      "github.com/example/app",
+		Version:   "v1.0.0",
+		GoVersion: "go1.21.5",
+		Dependencies: []DependencyInfo{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0", Replace: &DependencyInfo{Path: "github.com/fork/cobra", Version: "v1.8.1"}},
+		},
+	}
+
+	mismatches := compareSlices([]UniversalSlice{
+		{Arch: "amd64", Info: amd64},
+		{Arch: "arm64", Info: arm64},
+		{Arch: "386", Error: "not a Go executable"},
+	})
+	if len(mismatches) != 2 {
+		t.Fatalf("Expected 2 mismatches, got %+v", mismatches)
+	}
+	if mismatches[0].Path != "github.com/spf13/cobra" || mismatches[0].Values["arm64"] != "v1.8.0 => github.com/fork/cobra@v1.8.1" {
+		t.Errorf("Unexpected replace mismatch: %+v", mismatches[0])
+	}
+	if mismatches[1].Path != "golang.org/x/sys" || mismatches[1].Values["arm64"] != "" {
+		t.Errorf("Unexpected missing dependency mismatch: %+v", mismatches[1])
+	}
+	if _, ok := mismatches[0].Values["386"]; ok {
+		t.Error("Slices that failed to parse should not be compared")
+	}
+}